@@ -0,0 +1,481 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// An archive holds the stored data of the assets, one after the other,
+// followed by an index and a footer:
+//
+//	data    the stored data of each asset
+//	index   per asset: uvarint name length, name, uvarint offset and
+//	        length of the data, one byte of flags (1: gzip compressed,
+//	        2: member of a solid group), for members uvarint offset and
+//	        length in the uncompressed group, uvarint mode, varint
+//	        modification time, uvarint link length, link
+//	footer  uint64 offset of the index, uint64 size of the archive,
+//	        16 bytes archive ID, 16 bytes archiveMagic
+//
+// Integers are little endian, offsets count from the start of the archive.
+// The ID is the start of the SHA-256 sum of the data and the index. Being
+// found through its footer, the archive may be appended to a file, such as
+// an executable, or be embedded in one.
+const (
+	archiveMagic      = "go-bindata\x00arc\x00\x01"
+	archiveFooterSize = 48
+)
+
+// archiveEntry describes an asset in the index of an archive. The data
+// of a member of a solid group is found at member in the uncompressed
+// data located by offset and length.
+type archiveEntry struct {
+	offset  int64
+	length  int64
+	gzip    bool
+	solid   bool
+	member  int64
+	size    int64
+	mode    os.FileMode
+	modTime int64
+	link    string
+}
+
+// archive is an archive found in a file.
+type archive struct {
+	base    int64
+	size    int64
+	id      string
+	names   []string
+	entries map[string]archiveEntry
+}
+
+// archived reports whether the stored data is written to an archive.
+func (c *Config) archived() bool {
+	return len(c.Archive) > 0 && !c.Debug && !c.Dev
+}
+
+// writeArchive writes the stored data of the assets as an archive. Aliases
+// share the data of their target.
+func (p *packer) writeArchive(w io.Writer, c *Config, toc []Asset) error {
+	h := sha256.New()
+	cw := &countWriter{Writer: io.MultiWriter(w, h)}
+	stored := make(map[string]bool)
+
+	for i := range toc {
+		asset := &toc[i]
+		if len(asset.Alias) > 0 {
+			continue
+		}
+
+		entry := packedEntry{group: -1, offset: cw.n, gzip: !c.NoCompress}
+		if err := writeStoredData(cw, c, asset); err != nil {
+			return err
+		}
+
+		entry.length = cw.n - entry.offset
+		p.entries[p.index[asset.Func]] = entry
+		stored[asset.Func] = true
+	}
+
+	for i := range toc {
+		if len(toc[i].Alias) > 0 {
+			p.entries[i] = p.entries[p.index[toc[i].Alias]]
+		}
+	}
+
+	return p.writeIndex(w, cw, h, c, stored)
+}
+
+// writeIndex writes the index and the footer of an archive, whose data
+// was written to cw and hashed into h. The index lists the assets whose
+// data is stored in the archive, by the functions in stored, along with
+// their aliases. The ID and the size of the archive are recorded in the
+// packer.
+func (p *packer) writeIndex(w io.Writer, cw *countWriter, h hash.Hash, c *Config, stored map[string]bool) error {
+	indexOffset := cw.n
+	for i := range p.toc {
+		asset := &p.toc[i]
+		target := asset.Func
+		if len(asset.Alias) > 0 {
+			target = asset.Alias
+		}
+		if !stored[target] {
+			continue
+		}
+
+		// A member of a solid group is located by the compressed group
+		// and its place in the uncompressed data.
+		entry := p.entries[p.index[target]]
+		offset, length, flags := entry.offset, entry.length, byte(0)
+		if entry.group >= 0 {
+			group := p.groups[entry.group]
+			offset, length, flags = group.offset, group.length, 3
+		} else if entry.gzip {
+			flags = 1
+		}
+
+		_, mode, modTime, err := asset.metadata(c)
+		if err != nil {
+			return err
+		}
+
+		buf := make([]byte, 0, 8*binary.MaxVarintLen64+len(asset.Name)+len(asset.Link)+1)
+		buf = appendUvarint(buf, uint64(len(asset.Name)))
+		buf = append(buf, asset.Name...)
+		buf = appendUvarint(buf, uint64(offset))
+		buf = appendUvarint(buf, uint64(length))
+		buf = append(buf, flags)
+		if entry.group >= 0 {
+			buf = appendUvarint(buf, uint64(entry.offset))
+			buf = appendUvarint(buf, uint64(entry.length))
+		}
+		buf = appendUvarint(buf, uint64(mode))
+		buf = appendVarint(buf, modTime)
+		buf = appendUvarint(buf, uint64(len(asset.Link)))
+		buf = append(buf, asset.Link...)
+		if _, err := cw.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	p.id = string(h.Sum(nil)[:16])
+	p.size = cw.n + archiveFooterSize
+
+	footer := make([]byte, 16, archiveFooterSize)
+	binary.LittleEndian.PutUint64(footer, uint64(indexOffset))
+	binary.LittleEndian.PutUint64(footer[8:], uint64(p.size))
+	footer = append(footer, p.id...)
+	footer = append(footer, archiveMagic...)
+
+	_, err := w.Write(footer)
+	return err
+}
+
+// appendUvarint appends the uvarint encoding of x to buf.
+func appendUvarint(buf []byte, x uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], x)
+	return append(buf, b[:n]...)
+}
+
+// appendVarint appends the varint encoding of x to buf.
+func appendVarint(buf []byte, x int64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], x)
+	return append(buf, b[:n]...)
+}
+
+// errCorruptArchive is returned for archives whose footer is found, but
+// whose index or ID do not match.
+var errCorruptArchive = errors.New("corrupt archive")
+
+// readArchive reads the index of the archive ending at end in r, and
+// verifies its ID. It returns nil if there is none.
+func readArchive(r io.ReaderAt, end int64) (*archive, error) {
+	if end < archiveFooterSize {
+		return nil, nil
+	}
+
+	footer := make([]byte, archiveFooterSize)
+	if _, err := r.ReadAt(footer, end-archiveFooterSize); err != nil {
+		return nil, err
+	}
+	if string(footer[32:]) != archiveMagic {
+		return nil, nil
+	}
+
+	a := &archive{
+		size:    int64(binary.LittleEndian.Uint64(footer[8:])),
+		id:      string(footer[16:32]),
+		entries: make(map[string]archiveEntry),
+	}
+	a.base = end - a.size
+	indexOffset := int64(binary.LittleEndian.Uint64(footer))
+	if a.size < archiveFooterSize || a.base < 0 || indexOffset < 0 || indexOffset > a.size-archiveFooterSize {
+		return nil, errCorruptArchive
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, io.NewSectionReader(r, a.base, indexOffset)); err != nil {
+		return nil, err
+	}
+
+	index := make([]byte, a.size-archiveFooterSize-indexOffset)
+	if _, err := r.ReadAt(index, a.base+indexOffset); err != nil {
+		return nil, err
+	}
+	h.Write(index)
+	if string(h.Sum(nil)[:16]) != a.id {
+		return nil, errCorruptArchive
+	}
+
+	for len(index) > 0 {
+		var entry archiveEntry
+		name, ok := readIndexString(&index)
+		offset, ok1 := readIndexUvarint(&index)
+		length, ok2 := readIndexUvarint(&index)
+		if !ok || !ok1 || !ok2 || len(index) == 0 || offset > uint64(indexOffset) || length > uint64(indexOffset)-offset {
+			return nil, errCorruptArchive
+		}
+		entry.offset, entry.length, entry.gzip = int64(offset), int64(length), index[0]&1 != 0
+		entry.solid = index[0]&2 != 0
+		index = index[1:]
+		if entry.solid {
+			member, ok1 := readIndexUvarint(&index)
+			size, ok2 := readIndexUvarint(&index)
+			if !ok1 || !ok2 {
+				return nil, errCorruptArchive
+			}
+			entry.member, entry.size = int64(member), int64(size)
+		}
+
+		mode, ok := readIndexUvarint(&index)
+		modTime, k := binary.Varint(index)
+		if !ok || k <= 0 {
+			return nil, errCorruptArchive
+		}
+		index = index[k:]
+		entry.mode, entry.modTime = os.FileMode(mode), modTime
+
+		if entry.link, ok = readIndexString(&index); !ok {
+			return nil, errCorruptArchive
+		}
+
+		a.names = append(a.names, name)
+		a.entries[name] = entry
+	}
+
+	return a, nil
+}
+
+// readIndexUvarint reads a uvarint from the index.
+func readIndexUvarint(index *[]byte) (uint64, bool) {
+	x, k := binary.Uvarint(*index)
+	if k <= 0 {
+		return 0, false
+	}
+	*index = (*index)[k:]
+	return x, true
+}
+
+// readIndexString reads a string prefixed by its length from the index.
+func readIndexString(index *[]byte) (string, bool) {
+	n, ok := readIndexUvarint(index)
+	if !ok || n > uint64(len(*index)) {
+		return "", false
+	}
+	s := string((*index)[:n])
+	*index = (*index)[n:]
+	return s, true
+}
+
+// AppendArchive appends the archive written in Archive mode to an
+// executable, replacing an archive appended before. The executable then
+// serves the assets from the archive. On error the executable is left
+// untouched.
+func AppendArchive(executable, archivePath string) error {
+	src, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+
+	fi, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	if a, err := readArchive(src, fi.Size()); err != nil || a == nil || a.base != 0 {
+		return fmt.Errorf("%s is not an archive written by go-bindata", archivePath)
+	}
+
+	exe, err := os.Open(executable)
+	if err != nil {
+		return err
+	}
+
+	defer exe.Close()
+
+	fi, err = exe.Stat()
+	if err != nil {
+		return err
+	}
+
+	end := fi.Size()
+	a, err := readArchive(exe, end)
+	if err != nil {
+		return fmt.Errorf("%s: %v", executable, err)
+	}
+	if a != nil {
+		end = a.base
+	}
+
+	// The executable is replaced by a copy with the archive appended, so
+	// that it is left intact on error.
+	return writeFile(executable, func(w io.Writer) error {
+		if _, err := io.Copy(w, io.NewSectionReader(exe, 0, end)); err != nil {
+			return err
+		}
+		_, err := io.Copy(w, src)
+		return err
+	}, nil)
+}
+
+// writeArchiveCode writes the release code of Archive mode, reading the
+// stored data of each asset from the archive appended to the executable.
+// The code only accepts the archive written along with it.
+func writeArchiveCode(w io.Writer, c *Config, toc []Asset, p *packer) error {
+	imports := []string{"encoding/binary", "fmt", "io/ioutil", "os", "path/filepath", "strings", "sync", "time"}
+	if c.HttpFileSystem {
+		imports = append(imports, "bytes", "net/http")
+	}
+
+	read := `	data := make([]byte, length)
+	if _, err := a.file.ReadAt(data, a.base+offset); err != nil {
+		return nil, fmt.Errorf("read %q: %v", name, err)
+	}
+	return data, nil`
+	if !c.NoCompress && !c.NoUnpack {
+		imports = append(imports, "compress/gzip", "io")
+		read = `	if compressed {
+		return bindataGunzip(io.NewSectionReader(a.file, a.base+offset, length), name)
+	}
+
+` + read
+	}
+
+	_, err := fmt.Fprintf(w, `import (
+	"%s"
+)
+
+// _bindataArchiveID identifies the archive holding the data of the
+// assets, which go-bindata append adds to the end of the executable.
+const _bindataArchiveID = %q
+
+// bindataArchive reads the data of the assets from the archive appended
+// to the executable, which is opened on first access.
+type bindataArchive struct {
+	once sync.Once
+	file *os.File
+	base int64
+	err  error
+}
+
+var _bindataArchive bindataArchive
+
+func (a *bindataArchive) open() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	footer := make([]byte, %d)
+	if fi.Size() >= int64(len(footer)) {
+		_, err = f.ReadAt(footer, fi.Size()-int64(len(footer)))
+	}
+	if err != nil || fi.Size() < int64(len(footer)) || string(footer[32:]) != %q {
+		f.Close()
+		return fmt.Errorf("no archive appended to %%s", exe)
+	}
+	if string(footer[16:32]) != _bindataArchiveID {
+		f.Close()
+		return fmt.Errorf("the archive appended to %%s does not belong to this build", exe)
+	}
+
+	a.file = f
+	a.base = fi.Size() - int64(binary.LittleEndian.Uint64(footer[8:]))
+	return nil
+}
+
+func (a *bindataArchive) read(offset, length int64, compressed bool, name string) ([]byte, error) {
+	a.once.Do(func() { a.err = a.open() })
+	if a.err != nil {
+		return nil, fmt.Errorf("read %%q: %%v", name, a.err)
+	}
+
+%s
+}
+
+// bindataArchived locates the stored data of an asset in the archive,
+// along with its info.
+type bindataArchived struct {
+	offset  int64
+	length  int64
+	gzip    bool
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime int64
+	link    string
+}
+
+func (p *bindataArchived) load() (*asset, error) {
+	bytes, err := _bindataArchive.read(p.offset, p.length, p.gzip, p.name)
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: p.name, size: p.size, mode: p.mode, modTime: time.Unix(p.modTime, 0)}
+	a := &asset{bytes: bytes, info: info, link: p.link}
+	return a, nil
+}
+
+`, strings.Join(imports, "\"\n\t\""), p.id, archiveFooterSize, archiveMagic, read)
+	if err != nil {
+		return err
+	}
+
+	if err = writeGunzip(w, c); err != nil {
+		return err
+	}
+	if err = header_release_common(w); err != nil {
+		return err
+	}
+	if err = writeAssetFS(w, c); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, `// _bindataArchived is a table, holding the location and info of each asset.
+var _bindataArchived = [...]bindataArchived{
+`)
+	if err != nil {
+		return err
+	}
+
+	for i := range toc {
+		asset := &toc[i]
+		size, mode, modTime, err := asset.metadata(c)
+		if err != nil {
+			return err
+		}
+
+		entry := p.entries[i]
+		_, err = fmt.Fprintf(w, "\t{offset: %d, length: %d, gzip: %v, name: %q, size: %d, mode: os.FileMode(%d), modTime: %d, link: %q},\n",
+			entry.offset, entry.length, entry.gzip, asset.Name, size, mode, modTime, asset.Link)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeTOCFooter(w)
+}
@@ -0,0 +1,84 @@
+package bindata
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTranslateArchive(t *testing.T) {
+	dir, mod := newTestModule(t, printAssetsMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	files := map[string]string{"a.txt": "a\n", "b.bin": "\x00\xff", "copy.txt": "a\n", "empty": ""}
+	paths := make(map[string]string)
+	for name, content := range files {
+		paths[filepath.Join(in, name)] = content
+	}
+	writeTestFiles(t, paths)
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in}}
+	c.Prefix = in
+	c.Output = filepath.Join(mod, "bindata.go")
+	c.Archive = filepath.Join(dir, "assets.bin")
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+
+	fd, err := os.Open(c.Archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, _ := fd.Stat()
+	a, err := readArchive(fd, fi.Size())
+	fd.Close()
+	if err != nil || a == nil || a.base != 0 || a.size != fi.Size() {
+		t.Fatalf("unexpected archive %+v, %v", a, err)
+	}
+	if len(a.entries) != len(files) || a.entries["a.txt"] != a.entries["copy.txt"] || !a.entries["b.bin"].gzip {
+		t.Errorf("unexpected index %+v", a.entries)
+	}
+
+	g, err := ReadGenerated(c.Output)
+	if err != nil || len(g.Assets) != len(files) || !g.Assets[0].Archived {
+		t.Errorf("expected archived assets, got %v, %v", g, err)
+	} else if err := g.Restore(filepath.Join(dir, "restored")); err == nil {
+		t.Errorf("expected error restoring code without the data")
+	}
+
+	if err := AppendArchive(c.Output, c.Output); err == nil {
+		t.Errorf("expected error appending a file which is no archive")
+	}
+
+	exe := filepath.Join(dir, "x")
+	buildTestModule(t, mod, exe)
+	if out, err := exec.Command(exe).CombinedOutput(); err == nil || !strings.Contains(string(out), "no archive appended") {
+		t.Errorf("expected missing archive error, got %v\n%s", err, out)
+	}
+
+	if err := AppendArchive(exe, c.Archive); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.Stat(exe)
+	if err := AppendArchive(exe, c.Archive); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.Stat(exe); after.Size() != before.Size() {
+		t.Errorf("expected the archive to be replaced, size %d -> %d", before.Size(), after.Size())
+	}
+
+	out, err := exec.Command(exe).CombinedOutput()
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	for name, content := range files {
+		if line := name + "=" + strconv.Quote(content) + "\n"; !strings.Contains(string(out), line) {
+			t.Errorf("expected output to contain %q, got\n%s", line, out)
+		}
+	}
+}
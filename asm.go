@@ -0,0 +1,244 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// asmPath returns the path of the assembly file holding the stored data
+// in Assembly mode, named after the output file.
+func asmPath(output string) string {
+	return strings.TrimSuffix(output, ".go") + ".s"
+}
+
+// buildExpr converts build tags in the syntax of +build lines to the
+// expression of a //go:build line.
+func buildExpr(tags string) string {
+	options := strings.Fields(tags)
+	for i, option := range options {
+		terms := strings.Split(option, ",")
+		options[i] = strings.Join(terms, " && ")
+		if len(terms) > 1 && len(options) > 1 {
+			options[i] = "(" + options[i] + ")"
+		}
+	}
+	return strings.Join(options, " || ")
+}
+
+// asmWriter writes data as DATA directives of the symbol _bindataData,
+// width bytes per directive.
+type asmWriter struct {
+	w      io.Writer
+	width  int
+	offset int64
+	buf    []byte
+}
+
+func (a *asmWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		k := a.width - len(a.buf)
+		if k > len(p) {
+			k = len(p)
+		}
+
+		a.buf = append(a.buf, p[:k]...)
+		p = p[k:]
+
+		if len(a.buf) == a.width {
+			if err := a.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// size returns the number of bytes written so far.
+func (a *asmWriter) size() int64 {
+	return a.offset + int64(len(a.buf))
+}
+
+// flush writes the buffered data as a DATA directive.
+func (a *asmWriter) flush() error {
+	if len(a.buf) == 0 {
+		return nil
+	}
+
+	var lit bytes.Buffer
+	sw := &StringWriter{Writer: &lit}
+	if _, err := sw.Write(a.buf); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(a.w, "DATA ·_bindataData+%d(SB)/%d, $\"%s\"\n", a.offset, len(a.buf), lit.Bytes())
+	a.offset += int64(len(a.buf))
+	a.buf = a.buf[:0]
+	return err
+}
+
+// writeAssembly writes the assembly file of Assembly mode, defining the
+// symbol _bindataData which holds the stored data of all assets as an
+// archive, so that it can be found in built executables. The location of
+// each asset is recorded in the packer.
+func (p *packer) writeAssembly(w io.Writer, c *Config, toc []Asset) error {
+	_, err := fmt.Fprint(w, "// Code generated by go-bindata. (@generated) DO NOT EDIT.\n\n")
+	if err != nil {
+		return err
+	}
+
+	if len(c.Tags) > 0 {
+		_, err = fmt.Fprintf(w, "//go:build %s\n// +build %s\n\n", buildExpr(c.Tags), c.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err = fmt.Fprint(w, "#include \"textflag.h\"\n\n"); err != nil {
+		return err
+	}
+
+	width := c.Width
+	if width <= 0 {
+		width = 64
+	}
+
+	aw := &asmWriter{w: w, width: width}
+	if err = p.writeArchive(aw, c, toc); err != nil {
+		return err
+	}
+	if err = aw.flush(); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "GLOBL ·_bindataData(SB), RODATA|NOPTR, $%d\n", p.size)
+	return err
+}
+
+// writeStoredData writes the stored data of an asset, compressed unless
+// c.NoCompress is set, for the assembly file or the archive.
+func writeStoredData(w io.Writer, c *Config, asset *Asset) error {
+	fd, err := openAsset(asset)
+	if err != nil {
+		return err
+	}
+
+	defer fd.Close()
+
+	if c.NoCompress {
+		_, err = io.Copy(w, fd)
+		return err
+	}
+	return compress(w, c, asset, fd)
+}
+
+// writeAssemblyCode writes the release code of Assembly mode, declaring
+// the symbol defined by the assembly file and the table of assets.
+func writeAssemblyCode(w io.Writer, c *Config, toc []Asset, p *packer) error {
+	imports := []string{"fmt", "io/ioutil", "os", "path/filepath", "strings", "time"}
+	if c.HttpFileSystem {
+		imports = append(imports, "bytes", "net/http")
+	}
+
+	// Unless read-only slices are fine, the data is copied.
+	unpack := `	b := make([]byte, len(data))
+	copy(b, data)
+	return b, nil`
+	if c.NoMemCopy {
+		unpack = `	return data, nil`
+	}
+
+	if !c.NoCompress && !c.NoUnpack {
+		if !c.HttpFileSystem {
+			imports = append(imports, "bytes")
+		}
+		imports = append(imports, "compress/gzip", "io")
+		unpack = `	if compressed {
+		return bindataGunzip(bytes.NewReader(data), name)
+	}
+
+` + unpack
+	}
+
+	_, err := fmt.Fprintf(w, `import (
+	"%s"
+)
+
+// _bindataData holds the stored data of all assets. It is defined by
+// the DATA directives of %s, so the compiler never sees the data.
+var _bindataData [%d]byte
+
+// bindataAsm locates the stored data of an asset in _bindataData, along
+// with its info.
+type bindataAsm struct {
+	offset  int
+	length  int
+	gzip    bool
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime int64
+	link    string
+}
+
+func (p *bindataAsm) load() (*asset, error) {
+	bytes, err := bindataUnpack(_bindataData[p.offset:p.offset+p.length:p.offset+p.length], p.gzip, p.name)
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: p.name, size: p.size, mode: p.mode, modTime: time.Unix(p.modTime, 0)}
+	a := &asset{bytes: bytes, info: info, link: p.link}
+	return a, nil
+}
+
+func bindataUnpack(data []byte, compressed bool, name string) ([]byte, error) {
+%s
+}
+
+`, strings.Join(imports, "\"\n\t\""), filepath.Base(asmPath(c.Output)), p.size, unpack)
+	if err != nil {
+		return err
+	}
+
+	if err = writeGunzip(w, c); err != nil {
+		return err
+	}
+	if err = header_release_common(w); err != nil {
+		return err
+	}
+	if err = writeAssetFS(w, c); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, `// _bindataAsm is a table, holding the location and info of each asset.
+var _bindataAsm = [...]bindataAsm{
+`)
+	if err != nil {
+		return err
+	}
+
+	for i := range toc {
+		asset := &toc[i]
+		size, mode, modTime, err := asset.metadata(c)
+		if err != nil {
+			return err
+		}
+
+		entry := p.entries[i]
+		_, err = fmt.Fprintf(w, "\t{offset: %d, length: %d, gzip: %v, name: %q, size: %d, mode: os.FileMode(%d), modTime: %d, link: %q},\n",
+			entry.offset, entry.length, entry.gzip, asset.Name, size, mode, modTime, asset.Link)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeTOCFooter(w)
+}
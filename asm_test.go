@@ -0,0 +1,106 @@
+package bindata
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBuildExpr(t *testing.T) {
+	tests := []struct {
+		tags, expected string
+	}{
+		{"foo", "foo"},
+		{"foo,!bar", "foo && !bar"},
+		{"foo bar", "foo || bar"},
+		{"foo,bar baz", "(foo && bar) || baz"},
+	}
+	for _, test := range tests {
+		if got := buildExpr(test.tags); got != test.expected {
+			t.Errorf("buildExpr(%q): expected %q, got %q", test.tags, test.expected, got)
+		}
+	}
+}
+
+func TestTranslateAssembly(t *testing.T) {
+	dir, mod := newTestModule(t, printAssetsMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	files := map[string]string{
+		"a.txt":   "quotes \" and \\ and $ # // /* in text\n",
+		"b.bin":   "\x00\x01\a\b\f\v\xff",
+		"c.txt":   "quotes \" and \\ and $ # // /* in text\n",
+		"d.empty": "",
+	}
+	paths := make(map[string]string)
+	for name, content := range files {
+		paths[filepath.Join(in, name)] = content
+	}
+	writeTestFiles(t, paths)
+
+	for _, nocompress := range []bool{false, true} {
+		c := NewConfig()
+		c.Input = []InputConfig{{Path: in}}
+		c.Prefix = in
+		c.Output = filepath.Join(mod, "bindata.go")
+		c.Assembly = true
+		c.NoCompress = nocompress
+		c.Width = 5
+
+		if err := Translate(c); err != nil {
+			t.Fatal(err)
+		}
+
+		g, err := ReadGenerated(c.Output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(g.Assets) != len(files) {
+			t.Fatalf("expected %d assets, got %d", len(files), len(g.Assets))
+		}
+		for _, asset := range g.Assets {
+			if string(asset.Data) != files[asset.Name] {
+				t.Errorf("%s: expected %q, got %q", asset.Name, files[asset.Name], asset.Data)
+			}
+		}
+
+		for _, arch := range []string{"amd64", "arm64"} {
+			exe := filepath.Join(dir, "x-"+arch)
+			buildTestModule(t, mod, exe, "GOOS=linux", "GOARCH="+arch, "CGO_ENABLED=0")
+
+			if runtime.GOOS != "linux" || runtime.GOARCH != arch {
+				continue
+			}
+			out, err := exec.Command(exe).CombinedOutput()
+			if err != nil {
+				t.Fatalf("run: %v\n%s", err, out)
+			}
+			for name, content := range files {
+				if line := name + "=" + strconv.Quote(content) + "\n"; !strings.Contains(string(out), line) {
+					t.Errorf("expected output to contain %q, got\n%s", line, out)
+				}
+			}
+		}
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in}}
+	c.Output = filepath.Join(mod, "bindata.go")
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(mod, "bindata.s")); !os.IsNotExist(err) {
+		t.Errorf("expected the assembly file to be removed, got %v", err)
+	}
+
+	c.Assembly = true
+	c.Packed = true
+	if err := Translate(c); err == nil {
+		t.Errorf("expected error combining assembly with packed output")
+	}
+}
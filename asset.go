@@ -9,4 +9,13 @@ type Asset struct {
 	Path string // Full file path.
 	Name string // Key used in TOC -- name by which asset is referenced.
 	Func string // Function name for the procedure returning the asset contents.
+	Link string // Target of a preserved symbolic link, as read from the link.
+	Hash string // Hex encoded SHA-256 hash of the contents, if computed.
+
+	// Alias is the function name of another asset whose contents are
+	// shared by this asset, e.g. the target of a preserved symbolic link.
+	Alias string
+
+	Meta map[string]string // Metadata read from sidecar files.
+	Tags []string          // Tags read from sidecar files.
 }
@@ -0,0 +1,135 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// ReadBinary reads the assets from an executable built with code generated
+// by go-bindata. The assets are found in the archive appended to the
+// executable in Archive mode, and in the data of Assembly and packed mode,
+// which is laid out as an archive as well. The latter is searched for in
+// the data sections of ELF executables, or in the whole file otherwise.
+// In the default release mode each asset is a variable of its own, which
+// the linker places anywhere, so those assets are not found.
+func ReadBinary(filename string) (*Generated, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Generated{}
+	found := false
+
+	end := fi.Size()
+	a, err := readArchive(f, end)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	if a != nil {
+		if err = g.addArchive(f, a); err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+		found = true
+		end = a.base
+	}
+
+	var regions [][]byte
+	if ef, err := elf.NewFile(f); err == nil {
+		for _, s := range ef.Sections {
+			if s.Type != elf.SHT_PROGBITS || s.Flags&elf.SHF_ALLOC == 0 {
+				continue
+			}
+			data, err := s.Data()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", filename, err)
+			}
+			regions = append(regions, data)
+		}
+	} else {
+		data, err := ioutil.ReadAll(io.NewSectionReader(f, 0, end))
+		if err != nil {
+			return nil, err
+		}
+		regions = append(regions, data)
+	}
+
+	// Any occurrence of the magic may end an archive, which is only
+	// accepted if its ID matches its contents.
+	for _, data := range regions {
+		for i := 0; ; {
+			n := bytes.Index(data[i:], []byte(archiveMagic))
+			if n < 0 {
+				break
+			}
+			i += n + len(archiveMagic)
+
+			r := bytes.NewReader(data)
+			if a, err := readArchive(r, int64(i)); err == nil && a != nil {
+				if err = g.addArchive(r, a); err != nil {
+					return nil, fmt.Errorf("%s: %v", filename, err)
+				}
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%s: no assets of go-bindata found; the assets of default release builds cannot be found, generate the code with -packed, -solid, -asm or -archive", filename)
+	}
+	return g, nil
+}
+
+// addArchive adds the assets of an archive found in r.
+func (g *Generated) addArchive(r io.ReaderAt, a *archive) error {
+	groups := make(map[int64][]byte)
+	for _, name := range a.names {
+		entry := a.entries[name]
+
+		data, ok := groups[entry.offset]
+		if !ok {
+			data = make([]byte, entry.length)
+			if _, err := r.ReadAt(data, a.base+entry.offset); err != nil {
+				return err
+			}
+			if entry.gzip {
+				var err error
+				if data, err = gunzip(data); err != nil {
+					return fmt.Errorf("read %q: %v", name, err)
+				}
+			}
+		}
+		if entry.solid {
+			groups[entry.offset] = data
+			if entry.member > int64(len(data)) || entry.size > int64(len(data))-entry.member {
+				return fmt.Errorf("read %q: %v", name, errCorruptArchive)
+			}
+			data = data[entry.member : entry.member+entry.size]
+		}
+
+		g.Assets = append(g.Assets, GeneratedAsset{
+			Name:    name,
+			Data:    data,
+			Size:    int64(len(data)),
+			Mode:    entry.mode,
+			ModTime: entry.modTime,
+			Link:    entry.link,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package bindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadBinary(t *testing.T) {
+	dir, mod := newTestModule(t, printAssetsMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	if err := os.MkdirAll(filepath.Join(in, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{"a.txt": "a\n", "sub/b.bin": "\x00\xff", "copy.txt": "a\n"}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(in, name), []byte(content), 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The assets of the default release mode cannot be found.
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true}}
+	c.Output = filepath.Join(mod, "bindata.go")
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	exe := filepath.Join(dir, "default")
+	buildTestModule(t, mod, exe, "GOOS=linux", "GOARCH=arm64", "CGO_ENABLED=0")
+	if _, err := ReadBinary(exe); err == nil || !strings.Contains(err.Error(), "-packed, -solid, -asm or -archive") {
+		t.Errorf("expected an error naming the flags to use, got %v", err)
+	}
+
+	configs := map[string]func(c *Config){
+		"assembly": func(c *Config) { c.Assembly = true },
+		"archive":  func(c *Config) { c.Archive = filepath.Join(dir, "assets.bin"); c.NoCompress = true },
+		"packed":   func(c *Config) { c.Packed = true },
+		"solid":    func(c *Config) { c.Solid = 1024 },
+		"split":    func(c *Config) { c.Packed = true; c.NoCompress = true; c.SplitSize = 1 },
+	}
+	for name, configure := range configs {
+		c := NewConfig()
+		c.Input = []InputConfig{{Path: in, Recursive: true}}
+		c.Prefix = in
+		c.Output = filepath.Join(mod, "bindata.go")
+		c.ModTime = 12345
+		configure(c)
+		if err := Translate(c); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		exe := filepath.Join(dir, name)
+		buildTestModule(t, mod, exe, "GOOS=linux", "GOARCH=arm64", "CGO_ENABLED=0")
+		if len(c.Archive) > 0 {
+			if _, err := ReadBinary(exe); err == nil {
+				t.Errorf("%s: expected error reading an executable without assets", name)
+			}
+			if err := AppendArchive(exe, c.Archive); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		g, err := ReadBinary(exe)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(g.Assets) != len(files) {
+			t.Fatalf("%s: expected %d assets, got %d", name, len(files), len(g.Assets))
+		}
+		for _, asset := range g.Assets {
+			if string(asset.Data) != files[asset.Name] || asset.Mode != 0640 || asset.ModTime != 12345 {
+				t.Errorf("%s: unexpected asset %+v", name, asset)
+			}
+		}
+
+		out := filepath.Join(dir, "out-"+name)
+		if err := g.Restore(out); err != nil {
+			t.Fatal(err)
+		}
+		if data, err := ioutil.ReadFile(filepath.Join(out, "sub", "b.bin")); err != nil || string(data) != files["sub/b.bin"] {
+			t.Errorf("%s: unexpected restored file %q, %v", name, data, err)
+		}
+	}
+}
@@ -5,19 +5,24 @@
 package bindata
 
 import (
-	"fmt"
 	"io"
 )
 
 var (
 	newline    = []byte{'\n'}
-	dataindent = []byte{'\t', '\t'}
+	dataindent = []byte{'\t'}
 	space      = []byte{' '}
 )
 
+// ByteWriter writes data as the elements of a byte slice literal, in rows
+// of Width bytes, or 12 if Width is not set. A row also ends after a
+// newline in the data, so inserting bytes only changes the rows around
+// the insertion. Rows are indented by a tab, as gofmt lays out a literal
+// of a top-level declaration.
 type ByteWriter struct {
 	io.Writer
-	c int
+	Width int
+	c     int
 }
 
 func (w *ByteWriter) Write(p []byte) (n int, err error) {
@@ -25,20 +30,30 @@ func (w *ByteWriter) Write(p []byte) (n int, err error) {
 		return
 	}
 
-	for n = range p {
-		if w.c%12 == 0 {
-			w.Writer.Write(newline)
-			w.Writer.Write(dataindent)
-			w.c = 0
+	width := w.Width
+	if width <= 0 {
+		width = 12
+	}
+
+	buf := make([]byte, 0, 6*len(p)+3*len(p)/width+3)
+	for _, b := range p {
+		if w.c == 0 {
+			buf = append(buf, newline...)
+			buf = append(buf, dataindent...)
 		} else {
-			w.Writer.Write(space)
+			buf = append(buf, space...)
 		}
 
-		fmt.Fprintf(w.Writer, "0x%02x,", p[n])
+		buf = append(buf, '0', 'x', lowerHex[b/16], lowerHex[b%16], ',')
 		w.c++
+		if w.c >= width || b == '\n' {
+			w.c = 0
+		}
 	}
 
-	n++
+	if _, err = w.Writer.Write(buf); err != nil {
+		return 0, err
+	}
 
-	return
+	return len(p), nil
 }
@@ -0,0 +1,268 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// cacheKeyFile is the name of the file in the cache directory recording
+// the key of the last conversion and the hashes of the files it produced.
+const cacheKeyFile = "key"
+
+// DefaultCacheDir returns the default cache directory for the given output
+// file: a directory named after the output inside `.bindata-cache` next to it.
+func DefaultCacheDir(output string) string {
+	dir, name := filepath.Split(output)
+	return filepath.Join(dir, ".bindata-cache", name)
+}
+
+// cacheKey hashes everything the generated output depends on: the
+// generator itself, the configuration and the names, contents and metadata
+// of all assets and directories. It sets the content hash of every asset.
+func cacheKey(c *Config, toc, dirs []Asset) (string, error) {
+	h := sha256.New()
+
+	// A rebuilt generator may produce a different output.
+	if exe, err := os.Executable(); err == nil {
+		if sum, err := hashFile(exe); err == nil {
+			fmt.Fprintf(h, "generator %s\n", sum)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(h, "wd %q\n", wd)
+
+	// Regular expressions are written as their source, as pointers would
+	// differ between runs. Metadata rules are reflected in the metadata of
+	// the assets below.
+	cfg := *c
+	cfg.Input = nil
+	cfg.Ignore = nil
+	cfg.MetadataRules = nil
+	cfg.Log = nil
+	cfg.cached = nil
+	cfg.gitTimes = nil
+	fmt.Fprintf(h, "config %+v\n", cfg)
+	for _, re := range c.Ignore {
+		fmt.Fprintf(h, "ignore %q\n", re.String())
+	}
+	for _, input := range c.Input {
+		fmt.Fprintf(h, "input %q %v %q %q %v\n", input.Path, input.Recursive, input.Prefix, input.Mount, input.Symlinks)
+		for _, r := range input.Rename {
+			fmt.Fprintf(h, "rename %q %q\n", r.Pattern.String(), r.Replace)
+		}
+	}
+
+	for i := range toc {
+		if err := toc[i].hash(); err != nil {
+			return "", err
+		}
+		size, mode, modTime, err := toc[i].metadata(c)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "asset %q %q %q %q %q %q %d %d %d %s %v\n",
+			toc[i].Name, toc[i].Func, toc[i].Path, toc[i].Link, toc[i].Alias, toc[i].Hash,
+			size, mode, modTime, sortedMeta(toc[i].Meta), toc[i].Tags)
+	}
+
+	for i := range dirs {
+		size, mode, modTime, err := dirs[i].metadata(c)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "dir %q %q %d %d %d\n", dirs[i].Name, dirs[i].Path, size, mode, modTime)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sortedMeta formats asset metadata in a stable order.
+func sortedMeta(meta map[string]string) string {
+	pairs := make([]string, 0, len(meta))
+	for k, v := range meta {
+		pairs = append(pairs, fmt.Sprintf("%q=%q", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// hash sets the SHA-256 hash of the contents of the asset.
+func (asset *Asset) hash() error {
+	if len(asset.Hash) > 0 {
+		return nil
+	}
+
+	fd, err := openAsset(asset)
+	if err != nil {
+		return err
+	}
+
+	defer fd.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, fd); err != nil {
+		return err
+	}
+
+	asset.Hash = hex.EncodeToString(h.Sum(nil))
+	return nil
+}
+
+// hashFile returns the hex encoded SHA-256 hash of a file.
+func hashFile(path string) (string, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer fd.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, fd); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// outputSums returns a line for each of the output files holding its hash.
+func outputSums(files []string) (string, error) {
+	var sums []string
+	for _, file := range files {
+		sum, err := hashFile(file)
+		if err != nil {
+			return "", err
+		}
+		sums = append(sums, sum+" "+file)
+	}
+	return strings.Join(sums, "\n"), nil
+}
+
+// cacheUpToDate reports whether the output files were generated from the
+// same key and have not been modified since.
+func cacheUpToDate(c *Config, key string, files []string) bool {
+	data, err := ioutil.ReadFile(filepath.Join(c.Cache, cacheKeyFile))
+	if err != nil {
+		return false
+	}
+
+	sums, err := outputSums(files)
+	return err == nil && string(data) == key+"\n"+sums+"\n"
+}
+
+// writeCacheKey records the key of a conversion along with the hashes of
+// the output files it produced, and drops compressed payloads which were
+// not used by the conversion, for an asset or a solid group.
+func writeCacheKey(c *Config, key string, toc []Asset, files []string) error {
+	sums, err := outputSums(files)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(c.Cache, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(c.Cache, cacheKeyFile), []byte(key+"\n"+sums+"\n"), 0644)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+	for i := range toc {
+		used[toc[i].Hash+".gz"] = true
+	}
+	for hash := range c.cached {
+		used[hash+".gz"] = true
+	}
+
+	list, err := ioutil.ReadDir(c.Cache)
+	if err != nil {
+		return err
+	}
+
+	for _, fi := range list {
+		if strings.HasSuffix(fi.Name(), ".gz") && !used[fi.Name()] {
+			os.Remove(filepath.Join(c.Cache, fi.Name()))
+		}
+	}
+
+	return nil
+}
+
+// compress writes the gzip compressed contents of r to w. With a cache
+// directory configured, the compressed payload of contents seen in a
+// previous run is copied from the cache instead of being compressed again.
+func compress(w io.Writer, c *Config, asset *Asset, r io.Reader) error {
+	if len(c.Cache) == 0 || len(asset.Hash) == 0 {
+		gz := gzip.NewWriter(w)
+		_, err := io.Copy(gz, r)
+		clErr := gz.Close()
+		if err != nil {
+			return err
+		}
+		return clErr
+	}
+
+	if c.cached == nil {
+		c.cached = make(map[string]bool)
+	}
+	c.cached[asset.Hash] = true
+
+	path := filepath.Join(c.Cache, asset.Hash+".gz")
+	if fd, err := os.Open(path); err == nil {
+		defer fd.Close()
+		_, err = io.Copy(w, bufio.NewReader(fd))
+		return err
+	}
+
+	err := os.MkdirAll(c.Cache, 0755)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(c.Cache, asset.Hash+".tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	bw := bufio.NewWriter(tmp)
+	gz := gzip.NewWriter(io.MultiWriter(w, bw))
+	_, err = io.Copy(gz, r)
+	clErr := gz.Close()
+	if err != nil {
+		return err
+	}
+	if clErr != nil {
+		return clErr
+	}
+	if err = bw.Flush(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
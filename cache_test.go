@@ -0,0 +1,159 @@
+package bindata
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTranslateWithCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	if err := os.Mkdir(in, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(in, name), []byte("contents of "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true}}
+	c.Prefix = in
+	c.Output = filepath.Join(dir, "bindata.go")
+	c.Cache = DefaultCacheDir(c.Output)
+
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	first, err := ioutil.ReadFile(c.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payloads, _ := filepath.Glob(filepath.Join(c.Cache, "*.gz"))
+	if len(payloads) != 2 {
+		t.Fatalf("expected 2 cached payloads, got %v", payloads)
+	}
+
+	// An unchanged tree must not touch the output.
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(c.Output, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(c.Output); err != nil || !fi.ModTime().Equal(old) {
+		t.Errorf("expected output not to be rewritten")
+	}
+
+	// A modified output is regenerated.
+	if err := ioutil.WriteFile(c.Output, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	second, err := ioutil.ReadFile(c.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("expected regenerated output to equal the first output")
+	}
+
+	// A changed file replaces its payload only.
+	if err := ioutil.WriteFile(filepath.Join(in, "b.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	changed, _ := filepath.Glob(filepath.Join(c.Cache, "*.gz"))
+	if len(changed) != 2 {
+		t.Fatalf("expected 2 cached payloads, got %v", changed)
+	}
+	kept := 0
+	for _, p := range changed {
+		for _, q := range payloads {
+			if p == q {
+				kept++
+			}
+		}
+	}
+	if kept != 1 {
+		t.Errorf("expected the payload of the unchanged file to be kept, got %v", changed)
+	}
+}
+
+func TestTranslateSolidWithCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	if err := os.Mkdir(in, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(in, name), []byte("contents of "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true}}
+	c.Prefix = in
+	c.Output = filepath.Join(dir, "bindata.go")
+	c.Cache = DefaultCacheDir(c.Output)
+	c.Solid = 1024
+
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	payloads, _ := filepath.Glob(filepath.Join(c.Cache, "*.gz"))
+	if len(payloads) != 1 {
+		t.Fatalf("expected the cached payload of the group, got %v", payloads)
+	}
+
+	// A second run reuses the payload of the group.
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(payloads[0], old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(c.Output, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(payloads[0]); err != nil || !fi.ModTime().Equal(old) {
+		t.Errorf("expected the payload of the group to be reused")
+	}
+}
+
+func TestCacheKeyIgnoresState(t *testing.T) {
+	c := NewConfig()
+	key, err := cacheKey(c, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// State kept during a conversion does not change the key of the next.
+	c.gitTimes = map[string]map[string]int64{"/src": {"a.txt": 1500000000}}
+	c.cached = map[string]bool{"0123": true}
+	if again, err := cacheKey(c, nil, nil); err != nil || again != key {
+		t.Errorf("expected key %s, got %s, %v", key, again, err)
+	}
+}
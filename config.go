@@ -6,9 +6,12 @@ package bindata
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strings"
 )
 
 // InputConfig defines options on a asset directory to be convert.
@@ -20,6 +23,208 @@ type InputConfig struct {
 	// Recusive defines whether subdirectories of Path
 	// should be recursively included in the conversion.
 	Recursive bool
+
+	// Prefix defines a path prefix which should be stripped from the
+	// names of the files found in this input. It overrides Config.Prefix.
+	// If left empty while Mount is set, the directory of Path is stripped.
+	Prefix string
+
+	// Mount defines a virtual directory in the asset namespace under which
+	// the stripped file names of this input are placed. For example,
+	// mounting web/dist/... with the prefix web/dist at static turns
+	// web/dist/css/app.css into static/css/app.css.
+	Mount string
+
+	// Rename holds rules which rewrite the asset names of this input after
+	// the prefix has been stripped and the mount path has been applied.
+	// The first rule whose pattern matches a name is applied.
+	Rename []RenameRule
+
+	// Symlinks defines how symbolic links found in this input are
+	// handled. Defaults to SymlinkFollow.
+	Symlinks SymlinkPolicy
+
+	// Files, when set, restricts the input to the listed files of the
+	// directory Path, instead of all of its files. Recursive is ignored.
+	Files []InputFile
+}
+
+// InputFile is a file listed in an input, as read by ReadFileList.
+type InputFile struct {
+	// Name is the name of the file in the directory of the input.
+	Name string
+
+	// AssetName, when set, is the exact asset name of the file. The
+	// prefix, mount path and rename rules of the input do not apply.
+	AssetName string
+}
+
+// SymlinkPolicy defines how symbolic links are handled.
+type SymlinkPolicy int
+
+const (
+	// SymlinkFollow embeds the files a link points to under the name of
+	// the link. Links to directories are walked, loops are skipped.
+	SymlinkFollow SymlinkPolicy = iota
+
+	// SymlinkSkip leaves symbolic links out of the generated output.
+	SymlinkSkip
+
+	// SymlinkError fails the conversion when a symbolic link is found.
+	SymlinkError
+
+	// SymlinkPreserve records a symbolic link as an alias of the asset it
+	// points to. Both share the same stored bytes, AssetInfo reports the
+	// os.ModeSymlink bit for the link and RestoreAssets recreates it as a
+	// symbolic link.
+	SymlinkPreserve
+)
+
+var symlinkPolicies = []string{"follow", "skip", "error", "preserve"}
+
+// String returns the name of the policy as accepted by ParseSymlinkPolicy.
+func (p SymlinkPolicy) String() string {
+	if p < 0 || int(p) >= len(symlinkPolicies) {
+		return fmt.Sprintf("SymlinkPolicy(%d)", int(p))
+	}
+	return symlinkPolicies[p]
+}
+
+// ParseSymlinkPolicy parses one of "follow", "skip", "error" or "preserve".
+func ParseSymlinkPolicy(name string) (SymlinkPolicy, error) {
+	for i, n := range symlinkPolicies {
+		if n == name {
+			return SymlinkPolicy(i), nil
+		}
+	}
+	return SymlinkFollow, fmt.Errorf("invalid symlink policy %q: expected one of %s", name, strings.Join(symlinkPolicies, ", "))
+}
+
+// Encoding defines how embedded data is written as Go literals.
+type Encoding int
+
+const (
+	// EncodingString writes the data of an asset as a single string
+	// literal on one line. Text is written as a raw string literal.
+	EncodingString Encoding = iota
+
+	// EncodingLines writes string literals wrapped onto several lines,
+	// concatenated with +. A line ends after Width bytes of data, or after
+	// a newline in the data, so a small change of an asset only touches
+	// few lines of the output. Text is written as a raw string literal.
+	EncodingLines
+
+	// EncodingBytes writes a byte slice literal, with rows of at most
+	// Width bytes which also end after a newline in the data. As the rodata
+	// hack needs a string, it cannot be combined with NoMemCopy.
+	EncodingBytes
+)
+
+var encodings = []string{"string", "lines", "bytes"}
+
+// String returns the name of the encoding as accepted by ParseEncoding.
+func (e Encoding) String() string {
+	if e < 0 || int(e) >= len(encodings) {
+		return fmt.Sprintf("Encoding(%d)", int(e))
+	}
+	return encodings[e]
+}
+
+// ParseEncoding parses one of "string", "lines" or "bytes".
+func ParseEncoding(name string) (Encoding, error) {
+	for i, n := range encodings {
+		if n == name {
+			return Encoding(i), nil
+		}
+	}
+	return EncodingString, fmt.Errorf("invalid encoding %q: expected one of %s", name, strings.Join(encodings, ", "))
+}
+
+// packed reports whether the assets are written in packed mode.
+func (c *Config) packed() bool {
+	return (c.Packed || c.Solid > 0) && !c.Debug && !c.Dev
+}
+
+// assembly reports whether the stored data is written as Go assembly.
+func (c *Config) assembly() bool {
+	return c.Assembly && !c.Debug && !c.Dev
+}
+
+// layered reports whether assets are looked up in layers before the
+// embedded assets.
+func (c *Config) layered() bool {
+	return c.LoadPacks || c.overlay()
+}
+
+// dynamic reports whether the assets are found on disk at runtime, by
+// looking them up in the inputs again, rather than fixed at generation time.
+func (c *Config) dynamic() bool {
+	return c.Debug || c.Dev
+}
+
+// overlay reports whether an overlay directory is consulted before the
+// embedded assets.
+func (c *Config) overlay() bool {
+	return c.Overlay || len(c.OverlayEnv) > 0
+}
+
+// width returns the number of data bytes per line of the encoding.
+func (c *Config) width() int {
+	switch {
+	case c.Width > 0:
+		return c.Width
+	case c.Encoding == EncodingBytes:
+		return 12
+	case c.Encoding == EncodingLines:
+		return 64
+	}
+	return 0
+}
+
+// RenameRule rewrites asset names matching Pattern to Replace, which may
+// refer to submatches as described for regexp.Regexp.Expand.
+type RenameRule struct {
+	Pattern *regexp.Regexp
+	Replace string
+}
+
+// ParseRenameRule parses a rename rule in the form `src=dst`, where src is
+// a regular expression and dst its replacement.
+func ParseRenameRule(rule string) (RenameRule, error) {
+	i := strings.Index(rule, "=")
+	if i < 0 {
+		return RenameRule{}, fmt.Errorf("invalid rename rule %q: expected src=dst", rule)
+	}
+
+	re, err := regexp.Compile(rule[:i])
+	if err != nil {
+		return RenameRule{}, fmt.Errorf("invalid rename rule %q: %v", rule, err)
+	}
+
+	return RenameRule{Pattern: re, Replace: rule[i+1:]}, nil
+}
+
+// assetName places a name with its prefix already stripped into the
+// asset namespace, applying the mount path and the rename rules.
+func (input *InputConfig) assetName(name string) string {
+	name = input.mountedName(name)
+
+	for _, rule := range input.Rename {
+		if rule.Pattern.MatchString(name) {
+			return rule.Pattern.ReplaceAllString(name, rule.Replace)
+		}
+	}
+
+	return name
+}
+
+// mountedName places a name with its prefix already stripped under the
+// mount path, without applying the rename rules.
+func (input *InputConfig) mountedName(name string) string {
+	if len(input.Mount) > 0 {
+		name = path.Join(strings.Trim(filepath.ToSlash(input.Mount), "/"), name)
+	}
+	return name
 }
 
 // Config defines a set of options for the asset conversion.
@@ -44,6 +249,7 @@ type Config struct {
 
 	// Prefix defines a path prefix which should be stripped from all
 	// file names when generating the keys in the table of contents.
+	// Inputs may override it with InputConfig.Prefix.
 	// For example, running without the `-prefix` flag, we get:
 	//
 	// 	$ go-bindata /path/to/templates
@@ -114,6 +320,135 @@ type Config struct {
 	// instance's function.When true,will generate relate code.
 	HttpFileSystem bool
 
+	// AssetMeta means whether to read metadata and tags from sidecar files
+	// and generate the AssetMeta and AssetsWithTag functions. A sidecar
+	// file is named after the asset with a `.meta.json` suffix and holds
+	// a JSON object like {"meta": {"locale": "de"}, "tags": ["theme"]}.
+	// A `.bindatameta.json` file holds a list of such objects with an
+	// additional "pattern" glob, which apply to the matching files in its
+	// directory and below. Sidecar files are not embedded themselves.
+	AssetMeta bool
+
+	// SplitSize, when positive, moves the embedded data out of the output
+	// file into data files of the same package, named after the output
+	// file with a sequence number, e.g. bindata_data1.go, bindata_data2.go.
+	// Assets are added to a data file until it would hold more than
+	// SplitSize bytes of file contents. A larger asset gets a data file of
+	// its own. The output file keeps the API and the table of contents.
+	//
+	// Data files of previous runs which are no longer needed are removed.
+	// Debug builds embed no data and are never split.
+	SplitSize int64
+
+	// SplitDirs moves the embedded data of each top-level directory into
+	// data files of its own, as with SplitSize. Both may be combined.
+	SplitDirs bool
+
+	// NoDedup stores the contents of every asset, instead of storing
+	// identical contents once for all assets holding them.
+	NoDedup bool
+
+	// Log receives a summary of the conversion, such as the number of
+	// bytes saved by storing identical contents once. Nothing is logged
+	// when nil.
+	Log io.Writer
+
+	// Packed stores the data of all assets in a single string constant,
+	// or one per data file if split, instead of one variable and two
+	// functions per asset. A table records the location of each asset in
+	// the packed data along with its info, and Asset slices into it.
+	// Each string ends with the index of an archive, by which ReadBinary
+	// finds the assets in a built executable.
+	Packed bool
+
+	// Solid, when positive, compresses consecutive assets smaller than
+	// Solid bytes together as one stream, in groups of up to Solid bytes.
+	// Many small, similar files compress much better together. A group is
+	// decompressed when one of its assets is first accessed and is kept in
+	// memory from then on. Solid implies Packed and requires compression.
+	Solid int64
+
+	// Assembly writes the stored data of all assets to a Go assembly file
+	// next to the output file, named after it with a .s extension, as
+	// DATA directives of a single symbol. The output file declares the
+	// symbol and holds a table with the location and info of each asset,
+	// behind the same API as the release output. The compiler never
+	// parses the data, which keeps builds with large assets fast. Width
+	// sets the number of bytes per directive, 64 by default. Assembly
+	// cannot be combined with Packed, Solid, SplitSize, SplitDirs or
+	// Embed. Debug builds ignore Assembly.
+	Assembly bool
+
+	// Archive, when set, names a file to which the stored data of all
+	// assets is written as an archive with an index, instead of compiling
+	// it into the output. The archive is appended to the built executable
+	// with AppendArchive, or go-bindata append, and the generated code
+	// reads each asset from the executable when it is accessed, so that
+	// neither build times nor memory use depend on the size of the assets.
+	// The code only accepts the archive written along with it. Archive
+	// cannot be combined with Packed, Solid, SplitSize, SplitDirs, Embed or
+	// Assembly. Debug builds ignore Archive.
+	Archive string
+
+	// PackFile, when set, names a file to which the assets are written as
+	// an asset pack, instead of generating code. A pack holds the stored
+	// data and the info of its assets with an index and a checksum, and is
+	// loaded at runtime by code generated with LoadPacks. Output and the
+	// options about the generated code are ignored.
+	PackFile string
+
+	// LoadPacks generates the functions LoadPack and UnloadPack, which
+	// layer asset packs written with PackFile over the embedded assets.
+	// Asset, AssetInfo, AssetNames, AssetDir and AssetFile see the assets
+	// of the loaded packs, the last loaded first, and then the embedded
+	// ones. The code is written to a file next to the output file, named
+	// after it with a _layers suffix.
+	LoadPacks bool
+
+	// Overlay generates the function SetOverlay, which sets a directory
+	// on disk whose files take precedence over the embedded assets and the
+	// loaded packs, e.g. to patch a template without rebuilding. Asset,
+	// AssetInfo, AssetNames, AssetDir and AssetFile consult the directory
+	// on each access and merge its listings with the embedded ones. The
+	// code is written to the same file as with LoadPacks.
+	Overlay bool
+
+	// OverlayEnv, when set, names an environment variable from which the
+	// overlay directory is set at startup. It implies Overlay.
+	OverlayEnv string
+
+	// Encoding defines how the data of the assets is written to the
+	// generated code. Defaults to EncodingString.
+	Encoding Encoding
+
+	// Width is the number of bytes of data per line for EncodingLines and
+	// EncodingBytes. Defaults to 64 and 12 respectively.
+	Width int
+
+	// Cache defines a directory in which the key of the last conversion
+	// and the compressed payloads of the assets are kept. When the
+	// configuration, the generator and all input files are unchanged and
+	// the output has not been modified, the output is not rewritten at all.
+	// Otherwise payloads of unchanged files are reused instead of being
+	// compressed again. See DefaultCacheDir. Disabled when empty.
+	Cache string
+
+	// Embed leaves the embedding of the asset files to the Go toolchain.
+	// The output file lists the files in //go:embed directives and holds
+	// a table with the info of each asset, behind the same API as the
+	// release output. The files must lie in the directory of the output
+	// file or below it, and the output requires Go 1.16. Debug builds
+	// ignore Embed.
+	//
+	// Unless NoLegacy is set, the release output is written next to it,
+	// to a file named after the output file with a _legacy suffix, which
+	// is built by older toolchains only.
+	Embed bool
+
+	// NoLegacy omits the release output for older toolchains in Embed
+	// mode.
+	NoLegacy bool
+
 	// Perform a debug build. This generates an asset file, which
 	// loads the asset contents directly from disk at their original
 	// location, instead of embedding the contents in the code.
@@ -123,6 +458,10 @@ type Config struct {
 	// want your code to access the latest version of the asset.
 	// Only in release mode, will the assets actually be embedded
 	// in the code. The default behaviour is Release mode.
+	//
+	// The debug code looks up the inputs again on each access, honouring
+	// the prefix, mount paths, rename rules, recursion and ignore
+	// patterns, so that it sees the files added or removed since.
 	Debug bool
 
 	// Perform a dev build, which is nearly identical to the debug option. The
@@ -142,12 +481,34 @@ type Config struct {
 	// When nonzero, use this as unix timestamp for all files.
 	ModTime int64
 
+	// MetadataRules override the mode and modification time of the
+	// assets matching their patterns. They are applied in order, after
+	// Mode and ModTime, in release as well as debug builds.
+	MetadataRules []MetadataRule
+
 	// Ignores any filenames matching the regex pattern specified, e.g.
 	// path/to/file.ext will ignore only that file, or \\.gitignore
 	// will match any .gitignore file.
 	//
 	// This parameter can be provided multiple times.
 	Ignore []*regexp.Regexp
+
+	// IgnoreFiles lists the names of ignore files, e.g. ".gitignore" or
+	// ".bindataignore", which are read from the input directories and
+	// honoured with the semantics of gitignore(5): negation, anchoring,
+	// directory-only rules and nested files. When an input lies inside a
+	// git work tree, the files of its parent directories up to the root
+	// of the work tree apply as well. The ignore files themselves are not
+	// embedded. By default no ignore files are read.
+	IgnoreFiles []string
+
+	// gitTimes caches the commit times of the files of each git work tree,
+	// by the path of its root, for ModTimeGit.
+	gitTimes map[string]map[string]int64
+
+	// cached records the hashes of the payloads compressed with the cache
+	// during a conversion, which are kept in the cache directory.
+	cached map[string]bool
 }
 
 // NewConfig returns a default configuration struct.
@@ -177,6 +538,32 @@ func (c *Config) validate() error {
 		}
 	}
 
+	if c.Encoding == EncodingBytes && c.NoMemCopy {
+		return fmt.Errorf("the bytes encoding cannot be combined with nomemcopy")
+	}
+
+	if c.Encoding == EncodingBytes && (c.Packed || c.Solid > 0) {
+		return fmt.Errorf("the bytes encoding cannot be combined with packed")
+	}
+
+	if c.Solid > 0 && (c.NoCompress || c.NoUnpack) {
+		return fmt.Errorf("solid compression cannot be combined with nocompress or nounpack")
+	}
+
+	if c.Assembly && (c.Packed || c.Solid > 0 || c.SplitSize > 0 || c.SplitDirs || c.Embed) {
+		return fmt.Errorf("assembly output cannot be combined with packed, solid, split or embed")
+	}
+
+	if len(c.Archive) > 0 && (c.Packed || c.Solid > 0 || c.SplitSize > 0 || c.SplitDirs || c.Embed || c.Assembly) {
+		return fmt.Errorf("archive output cannot be combined with packed, solid, split, embed or assembly")
+	}
+
+	for i := range c.MetadataRules {
+		if err := c.MetadataRules[i].compile(); err != nil {
+			return err
+		}
+	}
+
 	if len(c.Output) == 0 {
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -187,22 +574,10 @@ func (c *Config) validate() error {
 		c.Output = filepath.Join(cwd, "bindata.go")
 	}
 
+	// A missing output file, or directory, is created when writing it.
 	stat, err := os.Lstat(c.Output)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return fmt.Errorf("output path: %v", err)
-		}
-
-		// File does not exist. This is fine, just make
-		// sure the directory it is to be in exists.
-		dir, _ := filepath.Split(c.Output)
-		if dir != "" {
-			err = os.MkdirAll(dir, 0744)
-
-			if err != nil {
-				return fmt.Errorf("create output directory: %v", err)
-			}
-		}
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("output path: %v", err)
 	}
 
 	if stat != nil && stat.IsDir() {
@@ -5,8 +5,8 @@
 package bindata
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -20,40 +20,204 @@ import (
 // in the given configuration.
 func Translate(c *Config) error {
 	var toc []Asset
+	var dirs []Asset
 
 	// Ensure our configuration has sane values.
 	err := c.validate()
 	if err != nil {
 		return err
 	}
+	c.cached = nil
 
 	var knownFuncs = make(map[string]int)
 	var visitedPaths = make(map[string]bool)
 	// Locate all the assets.
 	for _, input := range c.Input {
-		err = findFiles(input.Path, c.Prefix, input.Recursive, &toc, c.Ignore, knownFuncs, visitedPaths)
+		input = c.withPrefix(input)
+		rules, err := newIgnoreRules(c.IgnoreFiles, input.Path)
 		if err != nil {
 			return err
 		}
+		err = findFiles(input.Path, input, &toc, &dirs, c.Ignore, rules, knownFuncs, visitedPaths)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.AssetMeta {
+		toc, err = applySidecars(toc)
+		if err != nil {
+			return err
+		}
+	}
+
+	resolveLinks(toc)
+
+	// Mount paths and rename rules may map several files onto one name.
+	var names = make(map[string]string)
+	for _, asset := range toc {
+		if path, ok := names[asset.Name]; ok {
+			return fmt.Errorf("duplicate asset name %q for %s and %s", asset.Name, path, asset.Path)
+		}
+		names[asset.Name] = asset.Path
+	}
+
+	// Directories may be shared by several inputs.
+	dirs = uniqueDirs(dirs, names)
+
+	if !c.NoDedup && (!c.Debug && !c.Dev || len(c.PackFile) > 0) {
+		count, saved, err := dedupAssets(toc)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			c.logf("bindata: deduplicated %d files, saving %d bytes", count, saved)
+		}
+	}
+
+	if len(c.PackFile) > 0 {
+		return writePackFile(c, toc)
+	}
+
+	if c.Embed && !c.Debug && !c.Dev {
+		return translateEmbed(c, toc, dirs)
+	}
+
+	return generate(c, toc, dirs)
+}
+
+// generate writes the generated code for the given assets and directories
+// to the output file and its shards, unless the cache finds them up to date.
+func generate(c *Config, toc, dirs []Asset) error {
+	if c.packed() {
+		packAssets(toc)
+	} else if c.assembly() {
+		tableFuncs(toc, "_bindataAsm")
+	} else if c.archived() {
+		tableFuncs(toc, "_bindataArchived")
+	}
+
+	shards, err := splitAssets(c, toc)
+	if err != nil {
+		return err
+	}
+
+	files := []string{c.Output}
+	if c.assembly() {
+		files = append(files, asmPath(c.Output))
+	}
+	if c.archived() {
+		files = append(files, c.Archive)
+	}
+	if c.layered() {
+		files = append(files, layersPath(c.Output))
+	}
+	for _, s := range shards {
+		files = append(files, s.path)
+	}
+
+	var key string
+	if len(c.Cache) > 0 {
+		key, err = cacheKey(c, toc, dirs)
+		if err != nil {
+			return err
+		}
+		if cacheUpToDate(c, key, files) {
+			return removeStaleShards(c.Output, files)
+		}
+	}
+
+	err = writeOutput(c, toc, dirs, shards)
+	if err != nil {
+		return err
 	}
 
-	// Create output file.
-	fd, err := os.Create(c.Output)
+	err = removeStaleShards(c.Output, files)
 	if err != nil {
 		return err
 	}
 
-	defer fd.Close()
+	if !c.assembly() {
+		if err = removeGenerated(asmPath(c.Output)); err != nil {
+			return err
+		}
+	}
+	if !c.layered() {
+		if err = removeGenerated(layersPath(c.Output)); err != nil {
+			return err
+		}
+	}
+
+	if len(c.Cache) > 0 {
+		return writeCacheKey(c, key, toc, files)
+	}
+	return nil
+}
+
+// writeOutput writes the generated code for the given assets and
+// directories to the output file, and the embedded data to the given
+// shards, if any. In Assembly and Archive mode, the data is written to
+// the assembly file or the archive first. In the bytes encoding, the
+// files holding embedded data are not formatted.
+func writeOutput(c *Config, toc, dirs []Asset, shards []shard) error {
+	var pack *packer
+	if c.packed() || c.assembly() || c.archived() {
+		pack = newPacker(toc)
+	}
+
+	if c.assembly() {
+		err := writeFile(asmPath(c.Output), func(w io.Writer) error {
+			return pack.writeAssembly(w, c, toc)
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.archived() {
+		err := writeFile(c.Archive, func(w io.Writer) error {
+			return pack.writeArchive(w, c, toc)
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.layered() {
+		err := writeGoFile(layersPath(c.Output), func(w io.Writer) error {
+			return writeLayers(w, c)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range shards {
+		err := writeDataFile(shards[i].path, func(w io.Writer) error {
+			return writeShard(w, c, shards[i].assets, pack, i+1)
+		})
+		if err != nil {
+			return err
+		}
+	}
 
-	// Create a buffered writer for better performance.
-	bfd := bufio.NewWriter(fd)
-	defer bfd.Flush()
+	write := func(w io.Writer) error {
+		return writeCode(w, c, toc, dirs, len(shards) > 0, pack)
+	}
+	if len(shards) == 0 && !c.dynamic() && !c.Embed && !c.assembly() && !c.archived() {
+		return writeDataFile(c.Output, write)
+	}
+	return writeGoFile(c.Output, write)
+}
 
+// writeHeader writes the generated code header listing the sources of the
+// given assets, the build tags and the package declaration.
+func writeHeader(bfd io.Writer, c *Config, toc []Asset, header string) error {
 	// Write the header. This makes e.g. Github ignore diffs in generated files.
-	if _, err = fmt.Fprintf(bfd, "// Package %s Code generated by go-bindata. (@generated) DO NOT EDIT.\n", c.Package); err != nil {
+	if _, err := fmt.Fprintf(bfd, "// %sCode generated by go-bindata. (@generated) DO NOT EDIT.\n", header); err != nil {
 		return err
 	}
-	if _, err = fmt.Fprint(bfd, "// sources:\n"); err != nil {
+	if _, err := fmt.Fprint(bfd, "// sources:\n"); err != nil {
 		return err
 	}
 
@@ -81,6 +245,14 @@ func Translate(c *Config) error {
 
 	// Write package declaration.
 	_, err = fmt.Fprintf(bfd, "package %s\n\n", c.Package)
+	return err
+}
+
+// writeCode writes the generated code for the given assets and directories.
+// When sharded, the embedded data is left to the shards. In packed mode,
+// pack collects the location of the data of the assets.
+func writeCode(bfd io.Writer, c *Config, toc, dirs []Asset, sharded bool, pack *packer) error {
+	err := writeHeader(bfd, c, toc, fmt.Sprintf("Package %s ", c.Package))
 	if err != nil {
 		return err
 	}
@@ -88,6 +260,16 @@ func Translate(c *Config) error {
 	// Write assets.
 	if c.Debug || c.Dev {
 		err = writeDebug(bfd, c, toc)
+	} else if c.Embed {
+		err = writeEmbed(bfd, c, toc)
+	} else if c.assembly() {
+		err = writeAssemblyCode(bfd, c, toc, pack)
+	} else if c.archived() {
+		err = writeArchiveCode(bfd, c, toc, pack)
+	} else if pack != nil {
+		err = writePacked(bfd, c, toc, pack, sharded)
+	} else if sharded {
+		err = writeRelease(bfd, c, nil)
 	} else {
 		err = writeRelease(bfd, c, toc)
 	}
@@ -97,16 +279,24 @@ func Translate(c *Config) error {
 	}
 
 	// Write table of contents
-	if err := writeTOC(bfd, toc); err != nil {
+	if err := writeTOC(bfd, c, toc); err != nil {
+		return err
+	}
+	// Write asset metadata and tags
+	if err := writeAssetMeta(bfd, c, toc); err != nil {
+		return err
+	}
+	// Write directory metadata
+	if err := writeDirs(bfd, c, dirs); err != nil {
 		return err
 	}
 	// Write hierarchical tree of assets
-	if err := writeTOCTree(bfd, toc); err != nil {
+	if err := writeTOCTree(bfd, c, toc, dirs); err != nil {
 		return err
 	}
 
 	// Write restore procedure
-	return writeRestore(bfd)
+	return writeRestore(bfd, c)
 }
 
 // ByName implements sort.Interface for []os.FileInfo based on Name()
@@ -116,11 +306,38 @@ func (v ByName) Len() int           { return len(v) }
 func (v ByName) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
 func (v ByName) Less(i, j int) bool { return v[i].Name() < v[j].Name() }
 
+// inputDir returns the directory of an input path, which is the path
+// itself unless it refers to a single file.
+func inputDir(path string) string {
+	if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+		return filepath.Dir(path)
+	}
+	return path
+}
+
+// withPrefix returns the input with its prefix defaulting to the one of
+// the configuration, or to the directory of the input if it is mounted.
+func (c *Config) withPrefix(input InputConfig) InputConfig {
+	if len(input.Prefix) == 0 {
+		input.Prefix = c.Prefix
+		if len(input.Mount) > 0 {
+			input.Prefix = inputDir(input.Path)
+		}
+	}
+	return input
+}
+
 // findFiles recursively finds all the file paths in the given directory tree.
 // They are added to the given map as keys. Values will be safe function names
 // for each file, which will be used when generating the output code.
-func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regexp.Regexp, knownFuncs map[string]int, visitedPaths map[string]bool) error {
+// Files excluded by the ignore patterns or by the ignore files in rules
+// are skipped. Names are built from the prefix, mount path and rename
+// rules of the input, unless a listed file has an explicit name. Listed
+// files are found in the order of the list. If dirs is not nil, the directories visited are
+// added to it.
+func findFiles(dir string, input InputConfig, toc *[]Asset, dirs *[]Asset, ignore []*regexp.Regexp, rules *ignoreRules, knownFuncs map[string]int, visitedPaths map[string]bool) error {
 	dirpath := dir
+	prefix := input.Prefix
 	if len(prefix) > 0 {
 		dirpath, _ = filepath.Abs(dirpath)
 		prefix, _ = filepath.Abs(prefix)
@@ -133,10 +350,26 @@ func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regex
 	}
 
 	var list []os.FileInfo
+	var assetNames []string
 
 	if !fi.IsDir() {
 		dirpath = filepath.Dir(dirpath)
 		list = []os.FileInfo{fi}
+	} else if len(input.Files) > 0 {
+		// Listed files are read like single file inputs, following links.
+		for _, file := range input.Files {
+			fi, err := os.Stat(filepath.Join(dirpath, file.Name))
+			if err != nil {
+				return err
+			}
+			list = append(list, fi)
+			assetNames = append(assetNames, file.AssetName)
+		}
+
+		rules, err = rules.load(dirpath)
+		if err != nil {
+			return err
+		}
 	} else {
 		visitedPaths[dirpath] = true
 		fd, err := os.Open(dirpath)
@@ -151,11 +384,25 @@ func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regex
 			return err
 		}
 
+		rules, err = rules.load(dirpath)
+		if err != nil {
+			return err
+		}
+
+		if dirs != nil {
+			var asset Asset
+			asset.Name = input.assetName(stripPrefix(filepath.ToSlash(dirpath), prefix, dir))
+			asset.Path, _ = filepath.Abs(dirpath)
+			if len(asset.Name) > 0 {
+				*dirs = append(*dirs, asset)
+			}
+		}
+
 		// Sort to make output stable between invocations
 		sort.Sort(ByName(list))
 	}
 
-	for _, file := range list {
+	for i, file := range list {
 		var asset Asset
 		asset.Path = filepath.Join(dirpath, file.Name())
 		asset.Name = filepath.ToSlash(asset.Path)
@@ -167,17 +414,27 @@ func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regex
 				break
 			}
 		}
-		if ignoring {
+		if ignoring || rules.isIgnoreFile(file.Name()) || rules.match(asset.Path, file.IsDir()) {
 			continue
 		}
 
 		if file.IsDir() {
-			if recursive {
+			if input.Recursive {
 				recursivePath := filepath.Join(dir, file.Name())
 				visitedPaths[asset.Path] = true
-				findFiles(recursivePath, prefix, recursive, toc, ignore, knownFuncs, visitedPaths)
+				if err = findFiles(recursivePath, input, toc, dirs, ignore, rules, knownFuncs, visitedPaths); err != nil {
+					return err
+				}
 			}
 			continue
+		} else if file.Mode()&os.ModeSymlink == os.ModeSymlink && input.Symlinks == SymlinkSkip {
+			continue
+		} else if file.Mode()&os.ModeSymlink == os.ModeSymlink && input.Symlinks == SymlinkError {
+			return fmt.Errorf("symbolic link not allowed: %v", asset.Path)
+		} else if file.Mode()&os.ModeSymlink == os.ModeSymlink && input.Symlinks == SymlinkPreserve {
+			if asset.Link, err = os.Readlink(asset.Path); err != nil {
+				return err
+			}
 		} else if file.Mode()&os.ModeSymlink == os.ModeSymlink {
 			var linkPath string
 			if linkPath, err = os.Readlink(asset.Path); err != nil {
@@ -190,20 +447,14 @@ func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regex
 			}
 			if _, ok := visitedPaths[linkPath]; !ok {
 				visitedPaths[linkPath] = true
-				findFiles(asset.Path, prefix, recursive, toc, ignore, knownFuncs, visitedPaths)
+				findFiles(asset.Path, input, toc, dirs, ignore, rules, knownFuncs, visitedPaths)
 			}
 			continue
 		}
 
-		if strings.HasPrefix(asset.Name, prefix) {
-			asset.Name = asset.Name[len(prefix):]
-		} else {
-			asset.Name = filepath.Join(dir, file.Name())
-		}
-
-		// If we have a leading slash, get rid of it.
-		if len(asset.Name) > 0 && asset.Name[0] == '/' {
-			asset.Name = asset.Name[1:]
+		asset.Name = input.assetName(stripPrefix(asset.Name, prefix, filepath.Join(dir, file.Name())))
+		if len(assetNames) > 0 && len(assetNames[i]) > 0 {
+			asset.Name = assetNames[i]
 		}
 
 		// This shouldn't happen.
@@ -219,6 +470,68 @@ func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regex
 	return nil
 }
 
+// stripPrefix strips the prefix off a slash separated file name. If the
+// name does not start with the prefix, the fallback name is used.
+func stripPrefix(name, prefix, fallback string) string {
+	if strings.HasPrefix(name, prefix) {
+		name = name[len(prefix):]
+	} else {
+		name = fallback
+	}
+
+	// If we have a leading slash, get rid of it.
+	if len(name) > 0 && name[0] == '/' {
+		name = name[1:]
+	}
+
+	// The current directory is the root of the tree.
+	if name == "." {
+		name = ""
+	}
+
+	return name
+}
+
+// uniqueDirs drops directories recorded more than once, as well as
+// directories whose name is taken by a file.
+func uniqueDirs(dirs []Asset, files map[string]string) []Asset {
+	seen := make(map[string]bool)
+	unique := dirs[:0]
+	for _, dir := range dirs {
+		if _, ok := files[dir.Name]; ok || seen[dir.Name] {
+			continue
+		}
+		seen[dir.Name] = true
+		unique = append(unique, dir)
+	}
+
+	sort.Slice(unique, func(i, j int) bool { return unique[i].Name < unique[j].Name })
+	return unique
+}
+
+// resolveLinks turns preserved symbolic links pointing to another asset
+// into aliases of that asset, so their contents are only stored once.
+func resolveLinks(toc []Asset) {
+	targets := make(map[string]string)
+	for i := range toc {
+		if len(toc[i].Link) > 0 {
+			continue
+		}
+		if path, err := filepath.EvalSymlinks(toc[i].Path); err == nil {
+			targets[path] = toc[i].Func
+		}
+	}
+
+	for i := range toc {
+		if len(toc[i].Link) == 0 {
+			continue
+		}
+		if path, err := filepath.EvalSymlinks(toc[i].Path); err == nil {
+			toc[i].Alias = targets[path]
+		}
+	}
+}
+
 var regFuncName = regexp.MustCompile(`[^a-zA-Z0-9_]`)
 
 // safeFunctionName converts the given name into a name
@@ -1,6 +1,7 @@
 package bindata
 
 import (
+	"os"
 	"regexp"
 	"strings"
 	"testing"
@@ -19,7 +20,7 @@ func TestFindFiles(t *testing.T) {
 	var toc []Asset
 	var knownFuncs = make(map[string]int)
 	var visitedPaths = make(map[string]bool)
-	err := findFiles("testdata/dupname", "testdata/dupname", true, &toc, []*regexp.Regexp{}, knownFuncs, visitedPaths)
+	err := findFiles("testdata/dupname", InputConfig{Path: "testdata/dupname", Prefix: "testdata/dupname", Recursive: true}, &toc, nil, []*regexp.Regexp{}, nil, knownFuncs, visitedPaths)
 	if err != nil {
 		t.Errorf("expected to be no error: %+v", err)
 	}
@@ -34,14 +35,14 @@ func TestFindFilesWithSymlinks(t *testing.T) {
 
 	var knownFuncs = make(map[string]int)
 	var visitedPaths = make(map[string]bool)
-	err := findFiles("testdata/symlinkSrc", "testdata/symlinkSrc", true, &tocSrc, []*regexp.Regexp{}, knownFuncs, visitedPaths)
+	err := findFiles("testdata/symlinkSrc", InputConfig{Path: "testdata/symlinkSrc", Prefix: "testdata/symlinkSrc", Recursive: true}, &tocSrc, nil, []*regexp.Regexp{}, nil, knownFuncs, visitedPaths)
 	if err != nil {
 		t.Errorf("expected to be no error: %+v", err)
 	}
 
 	knownFuncs = make(map[string]int)
 	visitedPaths = make(map[string]bool)
-	err = findFiles("testdata/symlinkParent", "testdata/symlinkParent", true, &tocTarget, []*regexp.Regexp{}, knownFuncs, visitedPaths)
+	err = findFiles("testdata/symlinkParent", InputConfig{Path: "testdata/symlinkParent", Prefix: "testdata/symlinkParent", Recursive: true}, &tocTarget, nil, []*regexp.Regexp{}, nil, knownFuncs, visitedPaths)
 	if err != nil {
 		t.Errorf("expected to be no error: %+v", err)
 	}
@@ -64,7 +65,7 @@ func TestFindFilesWithRecursiveSymlinks(t *testing.T) {
 
 	var knownFuncs = make(map[string]int)
 	var visitedPaths = make(map[string]bool)
-	err := findFiles("testdata/symlinkRecursiveParent", "testdata/symlinkRecursiveParent", true, &toc, []*regexp.Regexp{}, knownFuncs, visitedPaths)
+	err := findFiles("testdata/symlinkRecursiveParent", InputConfig{Path: "testdata/symlinkRecursiveParent", Prefix: "testdata/symlinkRecursiveParent", Recursive: true}, &toc, nil, []*regexp.Regexp{}, nil, knownFuncs, visitedPaths)
 	if err != nil {
 		t.Errorf("expected to be no error: %+v", err)
 	}
@@ -79,7 +80,7 @@ func TestFindFilesWithSymlinkedFile(t *testing.T) {
 
 	var knownFuncs = make(map[string]int)
 	var visitedPaths = make(map[string]bool)
-	err := findFiles("testdata/symlinkFile", "testdata/symlinkFile", true, &toc, []*regexp.Regexp{}, knownFuncs, visitedPaths)
+	err := findFiles("testdata/symlinkFile", InputConfig{Path: "testdata/symlinkFile", Prefix: "testdata/symlinkFile", Recursive: true}, &toc, nil, []*regexp.Regexp{}, nil, knownFuncs, visitedPaths)
 	if err != nil {
 		t.Errorf("expected to be no error: %+v", err)
 	}
@@ -88,3 +89,99 @@ func TestFindFilesWithSymlinkedFile(t *testing.T) {
 		t.Errorf("Only one asset should have been found.  Got %d: %v", len(toc), toc)
 	}
 }
+
+func TestFindFilesWithMount(t *testing.T) {
+	var toc []Asset
+
+	rule, err := ParseRenameRule(`^static/(\w+)/test\.asset$=static/$1.txt`)
+	if err != nil {
+		t.Fatalf("expected to be no error: %+v", err)
+	}
+
+	input := InputConfig{Path: "testdata/in", Prefix: "testdata/in", Mount: "/static/", Recursive: true, Rename: []RenameRule{rule}}
+	err = findFiles(input.Path, input, &toc, nil, []*regexp.Regexp{}, nil, make(map[string]int), make(map[string]bool))
+	if err != nil {
+		t.Errorf("expected to be no error: %+v", err)
+	}
+
+	expected := []string{"static/a.txt", "static/b.txt", "static/c.txt", "static/test.asset"}
+	if len(toc) != len(expected) {
+		t.Fatalf("Expected %d assets, got %d: %v", len(expected), len(toc), toc)
+	}
+	for i := range toc {
+		if toc[i].Name != expected[i] {
+			t.Errorf("Expected asset name %s, got %s", expected[i], toc[i].Name)
+		}
+	}
+}
+
+func TestFindFilesWithSymlinkPolicies(t *testing.T) {
+	for _, policy := range []SymlinkPolicy{SymlinkSkip, SymlinkError, SymlinkPreserve} {
+		var toc []Asset
+		var knownFuncs = make(map[string]int)
+		var visitedPaths = make(map[string]bool)
+		for _, dir := range []string{"testdata/symlinkSrc", "testdata/symlinkFile"} {
+			input := InputConfig{Path: dir, Prefix: "testdata", Recursive: true, Symlinks: policy}
+			err := findFiles(dir, input, &toc, nil, []*regexp.Regexp{}, nil, knownFuncs, visitedPaths)
+			if policy == SymlinkError {
+				if dir == "testdata/symlinkFile" && err == nil {
+					t.Errorf("%v: expected an error", policy)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%v: expected to be no error: %+v", policy, err)
+			}
+		}
+		if policy == SymlinkError {
+			continue
+		}
+
+		resolveLinks(toc)
+
+		switch policy {
+		case SymlinkSkip:
+			if len(toc) != 4 {
+				t.Errorf("%v: expected 4 assets, got %d: %v", policy, len(toc), toc)
+			}
+		case SymlinkPreserve:
+			if len(toc) != 5 {
+				t.Fatalf("%v: expected 5 assets, got %d: %v", policy, len(toc), toc)
+			}
+			link := toc[4]
+			if link.Name != "symlinkFile/file1" || link.Link != "../symlinkSrc/file1" || link.Alias != toc[0].Func {
+				t.Errorf("%v: unexpected link asset %+v", policy, link)
+			}
+			// The size is the one of the contents served, not of the link.
+			size, _, _, err := link.metadata(NewConfig())
+			if fi, _ := os.Stat("testdata/symlinkSrc/file1"); err != nil || size != fi.Size() {
+				t.Errorf("%v: expected size %d of the target, got %d, %v", policy, fi.Size(), size, err)
+			}
+		}
+	}
+}
+
+func TestFindFilesWithDirs(t *testing.T) {
+	var toc []Asset
+	var dirs []Asset
+	input := InputConfig{Path: "testdata/dupname", Prefix: "testdata", Recursive: true}
+	err := findFiles(input.Path, input, &toc, &dirs, []*regexp.Regexp{}, nil, make(map[string]int), make(map[string]bool))
+	if err != nil {
+		t.Errorf("expected to be no error: %+v", err)
+	}
+
+	if len(dirs) != 2 || dirs[0].Name != "dupname" || dirs[1].Name != "dupname/foo" {
+		t.Fatalf("Expected directories dupname and dupname/foo, got %v", dirs)
+	}
+
+	c := NewConfig()
+	c.Mode = 0640
+	c.ModTime = 1500000000
+	size, mode, modTime, err := dirs[1].metadata(c)
+	if err != nil {
+		t.Fatalf("expected to be no error: %+v", err)
+	}
+	if size != 0 || os.FileMode(mode) != os.ModeDir|0750 || modTime != c.ModTime {
+		t.Errorf("Unexpected directory metadata: size %d, mode %v, modtime %d", size, os.FileMode(mode), modTime)
+	}
+}
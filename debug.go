@@ -7,6 +7,7 @@ package bindata
 import (
 	"fmt"
 	"io"
+	"os"
 )
 
 // writeDebug writes the debug code file.
@@ -28,7 +29,7 @@ func writeDebug(w io.Writer, c *Config, toc []Asset) error {
 		}
 	}
 
-	return nil
+	return writeDynamic(w, c)
 }
 
 // writeDebugHeader writes output file headers.
@@ -43,7 +44,9 @@ func writeDebugHeader(w io.Writer, c *Config) error {
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"`
 	} else {
@@ -51,7 +54,9 @@ func writeDebugHeader(w io.Writer, c *Config) error {
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"`
 	}
@@ -71,6 +76,7 @@ func bindataRead(path, name string) ([]byte, error) {
 type asset struct {
 	bytes []byte
 	info  os.FileInfo
+	link  string
 }
 
 type bindataFileInfo struct {
@@ -123,7 +129,16 @@ func writeDebugAsset(w io.Writer, c *Config, asset *Asset) error {
 		pathExpr = fmt.Sprintf("filepath.Join(rootDir, %q)", asset.Name)
 	}
 
-	_, err := fmt.Fprintf(w, `// %s reads file data from disk. It returns an error on failure.
+	if len(asset.Link) > 0 {
+		return writeDebugLink(w, c, asset, pathExpr)
+	}
+
+	override, err := debugInfoOverride(c, asset, 0)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, `// %s reads file data from disk. It returns an error on failure.
 func %s() (*asset, error) {
 	path := %s
 	name := %q
@@ -135,12 +150,82 @@ func %s() (*asset, error) {
 	fi, err := os.Stat(path)
 	if err != nil {
 		err = fmt.Errorf("Error reading asset info %%s at %%s: %%v", name, path, err)
-	}
+	}%s
 
 	a := &asset{bytes: bytes, info: fi}
 	return a, err
 }
 
-`, asset.Func, asset.Func, pathExpr, asset.Name)
+`, asset.Func, asset.Func, pathExpr, asset.Name, override)
+	return err
+}
+
+// debugInfoOverride returns code replacing the file info read from disk
+// with one carrying the mode and modification time overrides of the
+// configuration, or dropping the info with NoMetadata, or an empty string
+// if there are none.
+func debugInfoOverride(c *Config, asset *Asset, typ os.FileMode) (string, error) {
+	mode, modTime, err := asset.overrides(c, false)
+	if err != nil || (mode == 0 && modTime == 0 && !c.NoMetadata) {
+		return "", err
+	}
+
+	sizeExpr, modeExpr, modTimeExpr := "fi.Size()", "fi.Mode()", "fi.ModTime()"
+	if c.NoMetadata {
+		sizeExpr = "0"
+		modeExpr = fmt.Sprintf("os.FileMode(%d)", uint(typ))
+		modTimeExpr = "time.Unix(0, 0)"
+	}
+	if mode > 0 {
+		modeExpr = fmt.Sprintf("os.FileMode(%d)", mode|uint(typ))
+	}
+	if modTime > 0 {
+		modTimeExpr = fmt.Sprintf("time.Unix(%d, 0)", modTime)
+	}
+
+	return fmt.Sprintf(` else {
+		fi = bindataFileInfo{name: fi.Name(), size: %s, mode: %s, modTime: %s}
+	}`, sizeExpr, modeExpr, modTimeExpr), nil
+}
+
+// writeDebugLink write a debug entry for a preserved symbolic link.
+// The link and the contents of the file it points to are read from disk.
+func writeDebugLink(w io.Writer, c *Config, asset *Asset, pathExpr string) error {
+	override, err := debugInfoOverride(c, asset, os.ModeSymlink)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, `// %s reads a symbolic link from disk. It returns an error on failure.
+func %s() (*asset, error) {
+	path := %s
+	name := %q
+	link, err := os.Readlink(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading asset link %%s at %%s: %%v", name, path, err)
+	}
+
+	var bytes []byte
+	if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+		bytes, err = bindataRead(path, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fi, err := os.Lstat(path)
+	if err == nil {
+		// The size is the one of the contents served for the link.
+		fi = bindataFileInfo{name: fi.Name(), size: int64(len(bytes)), mode: fi.Mode(), modTime: fi.ModTime()}
+	}
+	if err != nil {
+		err = fmt.Errorf("Error reading asset info %%s at %%s: %%v", name, path, err)
+	}%s
+
+	a := &asset{bytes: bytes, info: fi, link: link}
+	return a, err
+}
+
+`, asset.Func, asset.Func, pathExpr, asset.Name, override)
 	return err
 }
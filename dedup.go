@@ -0,0 +1,61 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+)
+
+// dedupAssets makes assets with the same contents as an earlier asset an
+// alias of it, so the contents are stored once. Each asset keeps its own
+// info. It returns the number of assets turned into aliases and the number
+// of bytes of contents which are no longer stored.
+func dedupAssets(toc []Asset) (count int, saved int64, err error) {
+	first := make(map[string]string)
+	aliases := make(map[string]string)
+
+	for i := range toc {
+		asset := &toc[i]
+		if len(asset.Alias) > 0 {
+			continue
+		}
+
+		if err = asset.hash(); err != nil {
+			return
+		}
+
+		target, ok := first[asset.Hash]
+		if !ok {
+			first[asset.Hash] = asset.Func
+			continue
+		}
+
+		var size int64
+		if size, err = asset.dataSize(); err != nil {
+			return
+		}
+
+		asset.Alias = target
+		aliases[asset.Func] = target
+		count++
+		saved += size
+	}
+
+	// Aliases must refer to an asset which stores its contents.
+	for i := range toc {
+		if target, ok := aliases[toc[i].Alias]; ok {
+			toc[i].Alias = target
+		}
+	}
+
+	return
+}
+
+// logf writes a message to the log of the configuration, if any.
+func (c *Config) logf(format string, args ...interface{}) {
+	if c.Log != nil {
+		fmt.Fprintf(c.Log, format+"\n", args...)
+	}
+}
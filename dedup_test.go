@@ -0,0 +1,60 @@
+package bindata
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDedupAssets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{"a": "same", "b": "other", "c": "same", "d": "same"}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	toc := []Asset{
+		{Path: filepath.Join(dir, "a"), Name: "a", Func: "a"},
+		{Path: filepath.Join(dir, "b"), Name: "b", Func: "b"},
+		{Path: filepath.Join(dir, "c"), Name: "c", Func: "c"},
+		{Path: filepath.Join(dir, "c"), Name: "link", Func: "link", Link: "c", Alias: "c"},
+		{Path: filepath.Join(dir, "d"), Name: "d", Func: "d"},
+	}
+
+	count, saved, err := dedupAssets(toc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || saved != 8 {
+		t.Errorf("expected 2 files and 8 bytes saved, got %d and %d", count, saved)
+	}
+
+	expected := []string{"", "", "a", "a", "a"}
+	for i := range toc {
+		if toc[i].Alias != expected[i] {
+			t.Errorf("%s: expected alias %q, got %q", toc[i].Name, expected[i], toc[i].Alias)
+		}
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: dir, Recursive: true}}
+	c.Prefix = dir
+	c.Output = filepath.Join(dir, "out", "bindata.go")
+	var log bytes.Buffer
+	c.Log = &log
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	if log.String() != "bindata: deduplicated 2 files, saving 8 bytes\n" {
+		t.Errorf("unexpected log %q", log.String())
+	}
+}
@@ -0,0 +1,283 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// AssetChange describes an asset which differs between two generated files.
+type AssetChange struct {
+	Name string
+
+	// Old and New hold the asset in the old and the new file. Old is nil
+	// for added assets, New for removed ones.
+	Old *GeneratedAsset
+	New *GeneratedAsset
+}
+
+// ContentChanged reports whether the contents of a modified asset differ.
+func (ch *AssetChange) ContentChanged() bool {
+	return ch.Old != nil && ch.New != nil && !bytes.Equal(ch.Old.Data, ch.New.Data)
+}
+
+// InfoChanged reports whether the recorded mode, modification time or link
+// target of a modified asset differ.
+func (ch *AssetChange) InfoChanged() bool {
+	return ch.Old != nil && ch.New != nil &&
+		(ch.Old.Mode != ch.New.Mode || ch.Old.ModTime != ch.New.ModTime || ch.Old.Link != ch.New.Link)
+}
+
+// IsText reports whether the contents of the asset are text in both files,
+// as required for a unified diff. Missing contents count as text.
+func (ch *AssetChange) IsText() bool {
+	for _, asset := range []*GeneratedAsset{ch.Old, ch.New} {
+		if asset == nil {
+			continue
+		}
+		if text, _ := isText(bytes.NewReader(asset.Data)); !text {
+			return false
+		}
+	}
+	return true
+}
+
+// SizeDelta returns the number of bytes of contents added by the change.
+func (ch *AssetChange) SizeDelta() int64 {
+	var delta int64
+	if ch.New != nil {
+		delta += int64(len(ch.New.Data))
+	}
+	if ch.Old != nil {
+		delta -= int64(len(ch.Old.Data))
+	}
+	return delta
+}
+
+// DiffGenerated returns the assets which were added to, removed from or
+// modified in b compared to a, sorted by name. An asset is modified when
+// its contents or its recorded info differ.
+func DiffGenerated(a, b *Generated) []AssetChange {
+	changes := make(map[string]*AssetChange)
+	for i := range a.Assets {
+		changes[a.Assets[i].Name] = &AssetChange{Name: a.Assets[i].Name, Old: &a.Assets[i]}
+	}
+	for i := range b.Assets {
+		ch, ok := changes[b.Assets[i].Name]
+		if !ok {
+			ch = &AssetChange{Name: b.Assets[i].Name}
+			changes[ch.Name] = ch
+		}
+		ch.New = &b.Assets[i]
+	}
+
+	var list []AssetChange
+	for _, ch := range changes {
+		if ch.Old == nil || ch.New == nil || ch.ContentChanged() || ch.InfoChanged() {
+			list = append(list, *ch)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// diffOp is an operation of a line diff: an unchanged (' '), deleted ('-')
+// or inserted ('+') line. a and b count the lines of the old and the new
+// text before the line.
+type diffOp struct {
+	kind byte
+	a, b int
+}
+
+// maxDiffEdits limits the number of edits searched for by diffLines, which
+// needs memory quadratic in the number of edits. Texts which differ more
+// are diffed as replaced as a whole.
+const maxDiffEdits = 4000
+
+// diffLines returns a shortest edit script turning the lines a into b, as
+// found by the algorithm of Eugene W. Myers.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+	x, y := 0, 0
+	found := false
+	for d := 0; d <= n+m && d <= maxDiffEdits && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y = x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	var ops []diffOp
+	if !found {
+		for i := 0; i < n; i++ {
+			ops = append(ops, diffOp{'-', i, 0})
+		}
+		for j := 0; j < m; j++ {
+			ops = append(ops, diffOp{'+', n, j})
+		}
+		return ops
+	}
+
+	// Walk back from the end, collecting the operations in reverse.
+	x, y = n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		vd := trace[d]
+		get := func(k int) int { return vd[k+d] }
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		}
+		prevX, prevY := 0, 0
+		if d > 0 {
+			prevX = get(prevK)
+			prevY = prevX - prevK
+		}
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{' ', x, y})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			ops = append(ops, diffOp{'+', x, y})
+		} else {
+			x--
+			ops = append(ops, diffOp{'-', x, y})
+		}
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// splitLines splits text into lines, keeping their line feeds.
+func splitLines(text []byte) []string {
+	var lines []string
+	for len(text) > 0 {
+		i := bytes.IndexByte(text, '\n') + 1
+		if i == 0 {
+			i = len(text)
+		}
+		lines = append(lines, string(text[:i]))
+		text = text[i:]
+	}
+	return lines
+}
+
+// WriteUnifiedDiff writes a unified diff of the lines of the texts a and b
+// with the given number of lines of context. Nothing is written when the
+// texts are equal.
+func WriteUnifiedDiff(w io.Writer, oldName, newName string, a, b []byte, context int) error {
+	al, bl := splitLines(a), splitLines(b)
+	ops := diffLines(al, bl)
+
+	headed := false
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Changes separated by up to twice the context share a hunk.
+		start, end := i-context, i
+		if start < 0 {
+			start = 0
+		}
+		for j := i; j < len(ops) && j-end <= 2*context+1; j++ {
+			if ops[j].kind != ' ' {
+				end = j
+			}
+		}
+		stop := end + context + 1
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		if !headed {
+			if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", oldName, newName); err != nil {
+				return err
+			}
+			headed = true
+		}
+
+		hunk := ops[start:stop]
+		var aLen, bLen int
+		for _, op := range hunk {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		if _, err := fmt.Fprintf(w, "@@ -%s +%s @@\n", hunkRange(hunk[0].a, aLen), hunkRange(hunk[0].b, bLen)); err != nil {
+			return err
+		}
+
+		for _, op := range hunk {
+			line := ""
+			switch op.kind {
+			case '+':
+				line = bl[op.b]
+			default:
+				line = al[op.a]
+			}
+			if _, err := fmt.Fprintf(w, "%c%s", op.kind, line); err != nil {
+				return err
+			}
+			if len(line) == 0 || line[len(line)-1] != '\n' {
+				if _, err := fmt.Fprint(w, "\n\\ No newline at end of file\n"); err != nil {
+					return err
+				}
+			}
+		}
+
+		i = stop
+	}
+
+	return nil
+}
+
+// hunkRange formats the range of lines of a hunk, starting after the given
+// number of lines, as in unified diffs.
+func hunkRange(before, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, length)
+}
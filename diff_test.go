@@ -0,0 +1,80 @@
+package bindata
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDiffGenerated(t *testing.T) {
+	a := &Generated{Assets: []GeneratedAsset{
+		{Name: "same", Data: []byte("same"), Mode: 0644},
+		{Name: "removed", Data: []byte("removed")},
+		{Name: "contents", Data: []byte("old")},
+		{Name: "mode", Data: []byte("mode"), Mode: 0644},
+	}}
+	b := &Generated{Assets: []GeneratedAsset{
+		{Name: "mode", Data: []byte("mode"), Mode: 0755},
+		{Name: "contents", Data: []byte("newer")},
+		{Name: "same", Data: []byte("same"), Mode: 0644},
+		{Name: "added", Data: []byte("\x00binary")},
+	}}
+
+	changes := DiffGenerated(a, b)
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes, got %d", len(changes))
+	}
+
+	expected := []struct {
+		name           string
+		content, info  bool
+		delta          int64
+		added, removed bool
+	}{
+		{"added", false, false, 7, true, false},
+		{"contents", true, false, 2, false, false},
+		{"mode", false, true, 0, false, false},
+		{"removed", false, false, -7, false, true},
+	}
+	for i, e := range expected {
+		ch := &changes[i]
+		if ch.Name != e.name || ch.ContentChanged() != e.content || ch.InfoChanged() != e.info || ch.SizeDelta() != e.delta ||
+			(ch.Old == nil) != e.added || (ch.New == nil) != e.removed {
+			t.Errorf("unexpected change %d: %+v", i, *ch)
+		}
+	}
+
+	if changes[0].IsText() || !changes[1].IsText() {
+		t.Errorf("expected only the added asset to be binary")
+	}
+}
+
+func TestWriteUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		context  int
+		expected string
+	}{
+		{"same\n", "same\n", 3, ""},
+		{
+			"one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n",
+			"one\n2\nthree\nfour\nfive\nsix\nseven\neight\nnine", 3,
+			"--- a\n+++ b\n@@ -1,8 +1,9 @@\n one\n-two\n+2\n three\n four\n five\n six\n seven\n eight\n+nine\n\\ No newline at end of file\n",
+		},
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			"1\n3\n4\n5\n6\n7\n8\n9\n10\n11\n", 1,
+			"--- a\n+++ b\n@@ -1,3 +1,2 @@\n 1\n-2\n 3\n@@ -10 +9,2 @@\n 10\n+11\n",
+		},
+		{"", "new\n", 3, "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n"},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := WriteUnifiedDiff(&buf, "a", "b", []byte(test.a), []byte(test.b), test.context); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != test.expected {
+			t.Errorf("diff of %q and %q: expected\n%s\ngot\n%s", test.a, test.b, test.expected, buf.String())
+		}
+	}
+}
@@ -0,0 +1,762 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// scanRoot records an input for the debug code, which looks up the assets
+// on disk in it at runtime.
+type scanRoot struct {
+	path      string // Directory or file read from, relative to rootDir in Dev mode.
+	match     string // Path the ignore patterns are matched with, as findFiles matches them.
+	name      string // Asset name of the directory, or of the file.
+	file      bool
+	recursive bool
+	skipLinks bool
+	keepLinks bool
+	rename    []RenameRule
+	ignore    []scanIgnore
+}
+
+// scanIgnore is an ignore file applying to the paths relative to a root.
+// Its patterns cover the paths below base, made relative to the file by
+// stripping base, or by prepending lead for a file above the root.
+type scanIgnore struct {
+	base string
+	lead string
+	file *ignoreFile
+}
+
+// scanRoots returns the roots of the inputs, along with the ignore files
+// in effect for each. Names are built as findFiles does.
+func scanRoots(c *Config) ([]scanRoot, error) {
+	var roots []scanRoot
+	for _, input := range c.Input {
+		input = c.withPrefix(input)
+
+		// Each listed file is a root of its own.
+		files := input.Files
+		if len(files) == 0 {
+			files = []InputFile{{}}
+		}
+		for _, file := range files {
+			root, err := newScanRoot(c, input, file)
+			if err != nil {
+				return nil, err
+			}
+			roots = append(roots, root)
+		}
+	}
+	return roots, nil
+}
+
+// newScanRoot returns the root of an input, or of one of its listed files.
+func newScanRoot(c *Config, input InputConfig, file InputFile) (scanRoot, error) {
+	input.Path = filepath.Join(input.Path, file.Name)
+
+	dirpath := input.Path
+	prefix := input.Prefix
+	if len(prefix) > 0 {
+		dirpath, _ = filepath.Abs(dirpath)
+		prefix, _ = filepath.Abs(prefix)
+		prefix = filepath.ToSlash(prefix)
+	}
+
+	fi, err := os.Stat(dirpath)
+	if err != nil {
+		return scanRoot{}, err
+	}
+
+	root := scanRoot{
+		match:     dirpath,
+		file:      !fi.IsDir(),
+		recursive: input.Recursive,
+		skipLinks: input.Symlinks == SymlinkSkip,
+		keepLinks: input.Symlinks == SymlinkPreserve,
+	}
+
+	if root.file {
+		root.name = input.assetName(stripPrefix(filepath.ToSlash(dirpath), prefix, filepath.ToSlash(filepath.Clean(input.Path))))
+		if len(file.AssetName) > 0 {
+			root.name = file.AssetName
+		}
+	} else {
+		root.name = input.mountedName(stripPrefix(filepath.ToSlash(filepath.Clean(dirpath)), prefix, filepath.ToSlash(filepath.Clean(input.Path))))
+		root.rename = input.Rename
+		if root.ignore, err = scanIgnores(c, input.Path); err != nil {
+			return root, err
+		}
+	}
+
+	// Dev mode reads the files relative to rootDir, by their names, but
+	// matches the ignore patterns with the paths of generation.
+	if c.Dev {
+		root.path = filepath.FromSlash(root.name)
+		root.rename = nil
+	} else {
+		root.path, _ = filepath.Abs(dirpath)
+	}
+
+	return root, nil
+}
+
+// scanIgnores returns the ignore files in effect for the input directory:
+// those of its parent directories, as found by newIgnoreRules, and those
+// found in the directory and below, outermost first.
+func scanIgnores(c *Config, dir string) ([]scanIgnore, error) {
+	rules, err := newIgnoreRules(c.IgnoreFiles, dir)
+	if rules == nil || err != nil {
+		return nil, err
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.ToSlash(abs)
+
+	var ignores []scanIgnore
+	for _, f := range rules.files {
+		ignores = append(ignores, scanIgnore{lead: strings.TrimPrefix(base, f.base+"/"), file: f})
+	}
+
+	err = filepath.Walk(abs, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || !fi.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(abs, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			rel = ""
+		}
+		for _, name := range c.IgnoreFiles {
+			f, err := readIgnoreFile(filepath.Join(path, name))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			ignores = append(ignores, scanIgnore{base: filepath.ToSlash(rel), file: f})
+		}
+		return nil
+	})
+	return ignores, err
+}
+
+// writeDynamic writes the code finding the assets on disk at runtime, so
+// that the debug code sees the files added to and removed from the inputs
+// after generation. Assets known at generation are still loaded by their
+// functions, which apply the overrides of their info.
+func writeDynamic(w io.Writer, c *Config) error {
+	roots, err := scanRoots(c)
+	if err != nil {
+		return err
+	}
+
+	dir := "r.path"
+	if c.Dev {
+		dir = "filepath.Join(rootDir, r.path)"
+	}
+
+	_, err = fmt.Fprintf(w, `// bindataRoot is an input, in which the assets are looked up on disk.
+type bindataRoot struct {
+	path      string
+	match     string
+	name      string
+	file      bool
+	recursive bool
+	skipLinks bool
+	keepLinks bool
+	rename    []bindataRename
+	ignore    []bindataIgnoreFile
+}
+
+// bindataRename rewrites the asset names matching re to replace.
+type bindataRename struct {
+	re      *regexp.Regexp
+	replace string
+}
+
+// bindataIgnoreFile holds the patterns of an ignore file, which cover the
+// paths below base relative to the root, with base stripped or lead
+// prepended.
+type bindataIgnoreFile struct {
+	base     string
+	lead     string
+	patterns []bindataIgnorePattern
+}
+
+type bindataIgnorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// bindataInfoRule overrides the mode and modification time, where nonzero,
+// of the assets matching re, or of all of them if re is nil.
+type bindataInfoRule struct {
+	re      *regexp.Regexp
+	mode    os.FileMode
+	modTime int64
+}
+
+// bindataScan walks the inputs, and returns the path of each asset and of
+// each directory on disk, mapped to its name.
+func bindataScan() (map[string]string, map[string]string) {
+	files := make(map[string]string)
+	dirs := make(map[string]string)
+	for i := range _bindataRoots {
+		_bindataRoots[i].scan(files, dirs)
+	}
+	return files, dirs
+}
+
+func (r *bindataRoot) scan(files, dirs map[string]string) {
+	root := %[1]s
+	if r.file {
+		if path, ok := r.resolve(r.name, false); ok {
+			files[r.name] = path
+		}
+		return
+	}
+
+	// Only a directory re-entering itself through a link is dropped, the
+	// same directory may be reached through several paths.
+	ancestors := make(map[string]bool)
+	var walk func(dir, rel string)
+	walk = func(dir, rel string) {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			if ancestors[real] {
+				return
+			}
+			ancestors[real] = true
+			defer delete(ancestors, real)
+		}
+
+		list, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		if name := r.assetName(path.Join(r.name, rel)); len(name) != 0 {
+			dirs[name] = dir
+		}
+
+		for _, fi := range list {
+			filename := filepath.Join(dir, fi.Name())
+			frel := path.Join(rel, fi.Name())
+			fi, linked, ok := r.stat(filename, frel, fi)
+			if !ok {
+				continue
+			}
+
+			if fi.IsDir() {
+				if r.recursive || linked {
+					walk(filename, frel)
+				}
+				continue
+			}
+			files[r.assetName(path.Join(r.name, frel))] = filename
+		}
+	}
+	walk(root, "")
+}
+
+// stat applies the ignore files and the handling of links to an entry of a
+// directory, found at rel below the root. It returns the info of the file,
+// following links, whether it is a link, and false if the entry is skipped.
+func (r *bindataRoot) stat(filename, rel string, fi os.FileInfo) (os.FileInfo, bool, bool) {
+	if bindataIgnored(filepath.Join(r.match, filepath.FromSlash(rel)), fi.Name()) || r.ignored(rel, fi.IsDir()) {
+		return nil, false, false
+	}
+	if !fi.IsDir() && bindataSidecar(filename) {
+		return nil, false, false
+	}
+
+	linked := fi.Mode()&os.ModeSymlink != 0
+	if linked && r.skipLinks {
+		return nil, false, false
+	}
+	if linked && !r.keepLinks {
+		var err error
+		if fi, err = os.Stat(filename); err != nil {
+			return nil, false, false
+		}
+	}
+	return fi, linked, true
+}
+
+// rel returns the path of a name relative to the root, which is empty for
+// the root itself.
+func (r *bindataRoot) rel(name string) (string, bool) {
+	switch {
+	case len(r.name) == 0:
+		return name, true
+	case name == r.name:
+		return "", true
+	case strings.HasPrefix(name, r.name+"/"):
+		return name[len(r.name)+1:], true
+	}
+	return "", false
+}
+
+// find returns the path on disk of the named asset, or of the named
+// directory if dir is set. Names changed by rename rules can not be mapped
+// back to paths, those are found by walking the root.
+func (r *bindataRoot) find(name string, dir bool) (string, bool) {
+	if path, ok := r.resolve(name, dir); ok && r.assetName(name) == name {
+		return path, true
+	}
+	if r.file || len(r.rename) == 0 {
+		return "", false
+	}
+
+	files, dirs := make(map[string]string), make(map[string]string)
+	r.scan(files, dirs)
+	if dir {
+		path, ok := dirs[name]
+		return path, ok
+	}
+	path, ok := files[name]
+	return path, ok
+}
+
+// resolve maps a name to its path below the root, checking each directory
+// on the way as scan would walk it, so the root is not walked.
+func (r *bindataRoot) resolve(name string, dir bool) (string, bool) {
+	root := %[1]s
+	if r.file {
+		if dir || name != r.name || bindataIgnored(r.match, filepath.Base(r.match)) || bindataSidecar(root) {
+			return "", false
+		}
+		fi, err := os.Stat(root)
+		return root, err == nil && !fi.IsDir()
+	}
+
+	rel, ok := r.rel(name)
+	if !ok {
+		return "", false
+	}
+	var elems []string
+	if len(rel) > 0 {
+		elems = strings.Split(rel, "/")
+	}
+
+	// The directories on the way are the ancestors of the name, one of
+	// them reached again is a loop, which scan drops as well.
+	filename := root
+	ancestors := make(map[string]bool)
+	for i := 0; ; i++ {
+		if real, err := filepath.EvalSymlinks(filename); err == nil {
+			if ancestors[real] {
+				return "", false
+			}
+			ancestors[real] = true
+		}
+		if i == len(elems) {
+			fi, err := os.Stat(filename)
+			return filename, dir && err == nil && fi.IsDir()
+		}
+
+		filename = filepath.Join(filename, elems[i])
+		fi, err := os.Lstat(filename)
+		if err != nil {
+			return "", false
+		}
+		fi, linked, ok := r.stat(filename, strings.Join(elems[:i+1], "/"), fi)
+		if !ok {
+			return "", false
+		}
+		if !fi.IsDir() {
+			return filename, !dir && i == len(elems)-1
+		}
+		if !r.recursive && !linked {
+			return "", false
+		}
+	}
+}
+
+// list adds the entries of the named directory to children, reading only
+// that directory, or walking the root if it has rename rules. Roots below
+// the directory are entries of it. It reports whether the directory is
+// found.
+func (r *bindataRoot) list(name string, children map[string]bool) bool {
+	prefix := ""
+	if len(name) != 0 {
+		prefix = name + "/"
+	}
+
+	if len(r.rename) > 0 {
+		// The names of assets are no directories, those save the walk.
+		if _, ok := r.resolve(name, false); ok && r.assetName(name) == name {
+			return false
+		}
+		files, dirs := make(map[string]string), make(map[string]string)
+		r.scan(files, dirs)
+		_, found := dirs[name]
+		for _, names := range []map[string]string{files, dirs} {
+			for n := range names {
+				if strings.HasPrefix(n, prefix) && len(n) > len(prefix) {
+					found = true
+					children[strings.SplitN(n[len(prefix):], "/", 2)[0]] = true
+				}
+			}
+		}
+		return found
+	}
+
+	if strings.HasPrefix(r.name, prefix) && len(r.name) > len(prefix) {
+		if _, ok := r.resolve(r.name, !r.file); !ok {
+			return false
+		}
+		children[strings.SplitN(r.name[len(prefix):], "/", 2)[0]] = true
+		return true
+	}
+	if r.file {
+		return false
+	}
+
+	dir, ok := r.resolve(name, true)
+	if !ok {
+		return false
+	}
+	list, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	rel, _ := r.rel(name)
+	for _, fi := range list {
+		fi, linked, ok := r.stat(filepath.Join(dir, fi.Name()), path.Join(rel, fi.Name()), fi)
+		if ok && (!fi.IsDir() || r.recursive || linked) {
+			children[fi.Name()] = true
+		}
+	}
+	return true
+}
+
+// assetName applies the rename rules to a name.
+func (r *bindataRoot) assetName(name string) string {
+	for _, rule := range r.rename {
+		if rule.re.MatchString(name) {
+			return rule.re.ReplaceAllString(name, rule.replace)
+		}
+	}
+	return name
+}
+
+// ignored reports whether the path relative to the root is excluded by
+// the ignore files. As in git, a path is also excluded if any of its
+// parent directories is.
+func (r *bindataRoot) ignored(rel string, isDir bool) bool {
+	for i := 0; i < len(rel); i++ {
+		if rel[i] == '/' && r.excluded(rel[:i], true) {
+			return true
+		}
+	}
+	return r.excluded(rel, isDir)
+}
+
+// excluded applies every pattern which covers the path, the last match wins.
+func (r *bindataRoot) excluded(rel string, isDir bool) bool {
+	ignored := false
+	for _, f := range r.ignore {
+		p := rel
+		if len(f.base) > 0 {
+			if !strings.HasPrefix(p, f.base+"/") {
+				continue
+			}
+			p = p[len(f.base)+1:]
+		}
+		if len(f.lead) > 0 {
+			p = f.lead + "/" + p
+		}
+		for _, pattern := range f.patterns {
+			if pattern.dirOnly && !isDir {
+				continue
+			}
+			if pattern.re.MatchString(p) {
+				ignored = !pattern.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// bindataIgnored reports whether a file is excluded by the ignore patterns,
+// or is an ignore file itself.
+func bindataIgnored(path, name string) bool {
+	for _, re := range _bindataIgnore {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	for _, n := range _bindataIgnoreFiles {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// bindataSidecar reports whether a file holds the metadata of assets
+// rather than being one, when the metadata is read from sidecar files.
+func bindataSidecar(filename string) bool {
+	if !_bindataSidecars {
+		return false
+	}
+	if filepath.Base(filename) == %[3]q {
+		return true
+	}
+	if !strings.HasSuffix(filename, %[2]q) {
+		return false
+	}
+	fi, err := os.Stat(strings.TrimSuffix(filename, %[2]q))
+	return err == nil && !fi.IsDir()
+}
+
+// bindataDisk returns the function loading the named asset, if it is found
+// on disk. Assets added after generation are read with their info from disk.
+func bindataDisk(name string) (func() (*asset, error), bool) {
+	path, ok := bindataFind(name, false)
+	if !ok {
+		return nil, false
+	}
+	if f, ok := _bindata[name]; ok {
+		return f, true
+	}
+	return func() (*asset, error) {
+		bytes, err := bindataRead(path, name)
+		if err != nil {
+			return nil, err
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			err = fmt.Errorf("Error reading asset info %%s at %%s: %%v", name, path, err)
+		} else {
+			fi = bindataDiskInfo(name, fi)
+		}
+
+		a := &asset{bytes: bytes, info: fi}
+		return a, err
+	}, true
+}
+
+// bindataDiskInfo applies -nometadata and the overrides of the mode and
+// modification time to the info of an asset found on disk after
+// generation, as they are applied to the assets known at generation.
+func bindataDiskInfo(name string, fi os.FileInfo) os.FileInfo {
+	if !_bindataNoMetadata && len(_bindataInfoRules) == 0 {
+		return fi
+	}
+
+	size, mode, modTime := fi.Size(), fi.Mode(), fi.ModTime()
+	if _bindataNoMetadata {
+		size, mode, modTime = 0, mode&os.ModeType, time.Unix(0, 0)
+	}
+	overridden := false
+	for _, rule := range _bindataInfoRules {
+		if rule.re != nil && !rule.re.MatchString(name) {
+			continue
+		}
+		if rule.mode != 0 {
+			mode = mode&os.ModeType | rule.mode
+			overridden = true
+		}
+		if rule.modTime != 0 {
+			modTime = time.Unix(rule.modTime, 0)
+		}
+	}
+	if fi.IsDir() {
+		size = 0
+		if overridden {
+			// Like chmod a+X, directories are searchable where readable.
+			mode |= (mode & 0444) >> 2
+		}
+	}
+	return bindataFileInfo{name: fi.Name(), size: size, mode: mode, modTime: modTime}
+}
+
+// bindataFind returns the path on disk of the named asset, or directory.
+// The last input holding it wins, as it does in bindataScan.
+func bindataFind(name string, dir bool) (string, bool) {
+	for i := len(_bindataRoots) - 1; i >= 0; i-- {
+		if path, ok := _bindataRoots[i].find(name, dir); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// bindataDiskNames returns the names of the assets found on disk.
+func bindataDiskNames() []string {
+	files, _ := bindataScan()
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	return names
+}
+
+// bindataDiskDir returns the names of the entries of the named directory
+// found on disk, and whether the directory is found.
+func bindataDiskDir(name string) (map[string]bool, bool) {
+	children := make(map[string]bool)
+	found := len(name) == 0
+	for i := range _bindataRoots {
+		if _bindataRoots[i].list(name, children) {
+			found = true
+		}
+	}
+	return children, found
+}
+
+// bindataDirInfo returns the info of the named directory, if it is found
+// on disk.
+func bindataDirInfo(name string) (os.FileInfo, bool) {
+	if len(name) == 0 {
+		return nil, false
+	}
+	path, ok := bindataFind(name, true)
+	if !ok {
+		return nil, false
+	}
+	if info, ok := _bindataDirs[name]; ok {
+		return info, true
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	return bindataDiskInfo(name, info), true
+}
+
+`, dir, sidecarSuffix, dirMetaFile)
+	if err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprint(w, "// _bindataIgnore holds the ignore patterns, matched against the paths of the files.\nvar _bindataIgnore = []*regexp.Regexp{\n"); err != nil {
+		return err
+	}
+	for _, re := range c.Ignore {
+		if _, err = fmt.Fprintf(w, "\tregexp.MustCompile(%q),\n", re.String()); err != nil {
+			return err
+		}
+	}
+	if _, err = fmt.Fprintf(w, "}\n\n// _bindataIgnoreFiles holds the names of the ignore files.\nvar _bindataIgnoreFiles = %#v\n\n", append([]string{}, c.IgnoreFiles...)); err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(w, "// _bindataSidecars is set when sidecar files hold the metadata of assets.\nvar _bindataSidecars = %v\n\n", c.AssetMeta); err != nil {
+		return err
+	}
+	if err = writeInfoRules(w, c); err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprint(w, "// _bindataRoots is a table, holding the inputs.\nvar _bindataRoots = []bindataRoot{\n"); err != nil {
+		return err
+	}
+	for _, root := range roots {
+		if err = writeScanRoot(w, &root); err != nil {
+			return err
+		}
+	}
+	return writeTOCFooter(w)
+}
+
+// writeInfoRules writes -nometadata, the global overrides of the mode and
+// modification time and the metadata rules, which the debug code applies
+// to the assets found on disk after generation. Modification times taken
+// from git are left out, those of files committed after generation are
+// not known, so such files keep their time like untracked ones.
+func writeInfoRules(w io.Writer, c *Config) error {
+	_, err := fmt.Fprintf(w, "// _bindataNoMetadata is set when the size, mode and modification time\n// of the assets are not preserved.\nvar _bindataNoMetadata = %v\n\n", c.NoMetadata)
+	if err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprint(w, "// _bindataInfoRules holds the overrides of the mode and modification time\n// of the assets, the global ones first.\nvar _bindataInfoRules = []bindataInfoRule{\n"); err != nil {
+		return err
+	}
+	if mode := os.FileMode(c.Mode) & os.ModePerm; mode != 0 || c.ModTime > 0 {
+		if _, err = fmt.Fprintf(w, "\t{nil, os.FileMode(%d), %d},\n", mode, c.ModTime); err != nil {
+			return err
+		}
+	}
+	for i := range c.MetadataRules {
+		rule := &c.MetadataRules[i]
+		if err = rule.compile(); err != nil {
+			return err
+		}
+		modTime := rule.ModTime
+		if rule.ModTimeSource == ModTimeSourceDateEpoch {
+			if modTime, err = sourceDateEpoch(); err != nil {
+				return err
+			}
+		}
+		if _, err = fmt.Fprintf(w, "\t{regexp.MustCompile(%q), os.FileMode(%d), %d},\n", rule.re.String(), rule.Mode&modeMask, modTime); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprint(w, "}\n\n")
+	return err
+}
+
+// writeScanRoot writes an entry of the table of inputs.
+func writeScanRoot(w io.Writer, root *scanRoot) error {
+	_, err := fmt.Fprintf(w, "\t{\n\t\tpath: %q,\n\t\tmatch: %q,\n\t\tname: %q,\n\t\tfile: %v,\n\t\trecursive: %v,\n\t\tskipLinks: %v,\n\t\tkeepLinks: %v,\n",
+		root.path, root.match, root.name, root.file, root.recursive, root.skipLinks, root.keepLinks)
+	if err != nil {
+		return err
+	}
+
+	if len(root.rename) > 0 {
+		if _, err = fmt.Fprint(w, "\t\trename: []bindataRename{\n"); err != nil {
+			return err
+		}
+		for _, rule := range root.rename {
+			if _, err = fmt.Fprintf(w, "\t\t\t{regexp.MustCompile(%q), %q},\n", rule.Pattern.String(), rule.Replace); err != nil {
+				return err
+			}
+		}
+		if _, err = fmt.Fprint(w, "\t\t},\n"); err != nil {
+			return err
+		}
+	}
+
+	if len(root.ignore) > 0 {
+		if _, err = fmt.Fprint(w, "\t\tignore: []bindataIgnoreFile{\n"); err != nil {
+			return err
+		}
+		for _, ignore := range root.ignore {
+			if _, err = fmt.Fprintf(w, "\t\t\t{base: %q, lead: %q, patterns: []bindataIgnorePattern{\n", ignore.base, ignore.lead); err != nil {
+				return err
+			}
+			for _, p := range ignore.file.patterns {
+				if _, err = fmt.Fprintf(w, "\t\t\t\t{regexp.MustCompile(%q), %v, %v},\n", p.re.String(), p.negate, p.dirOnly); err != nil {
+					return err
+				}
+			}
+			if _, err = fmt.Fprint(w, "\t\t\t}},\n"); err != nil {
+				return err
+			}
+		}
+		if _, err = fmt.Fprint(w, "\t\t},\n"); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprint(w, "\t},\n")
+	return err
+}
@@ -0,0 +1,345 @@
+package bindata
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+const dynamicMain = `package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+func show() {
+	names := AssetNames()
+	sort.Strings(names)
+	fmt.Println(names)
+	dir, err := AssetDir("web")
+	sort.Strings(dir)
+	fmt.Println("dir", dir, err)
+}
+
+func main() {
+	show()
+	os.Remove("../in/a.txt")
+	os.MkdirAll("../in/new", 0755)
+	ioutil.WriteFile("../in/new/c.txt", []byte("new\n"), 0644)
+	ioutil.WriteFile("../in/new/c.tmp", []byte("new\n"), 0644)
+	ioutil.WriteFile("../in/skip/d.txt", []byte("new\n"), 0644)
+	show()
+	data, err := Asset("web/new/c.txt")
+	fmt.Printf("%q %v\n", data, err)
+	_, err = Asset("web/a.txt")
+	fmt.Println(err)
+	_, err = AssetDir("web/b.txt")
+	fmt.Println(err)
+	dir, err := AssetDir("web/new")
+	fmt.Println("dir", dir, err)
+	fmt.Println(RestoreAssets("../out", "web"))
+	data, err = ioutil.ReadFile("../out/web/new/c.txt")
+	fmt.Printf("%q %v\n", data, err)
+}
+`
+
+func TestTranslateDynamic(t *testing.T) {
+	dir, mod := newTestModule(t, dynamicMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	writeTestFiles(t, map[string]string{
+		filepath.Join(in, "a.txt"):          "a\n",
+		filepath.Join(in, "b.txt"):          "b\n",
+		filepath.Join(in, "b.bak"):          "b\n",
+		filepath.Join(in, "skip", "x.txt"):  "x\n",
+		filepath.Join(in, ".bindataignore"): "skip/\n*.tmp\n",
+	})
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true, Mount: "web"}}
+	c.Ignore = []*regexp.Regexp{regexp.MustCompile(`\.bak$`)}
+	c.IgnoreFiles = []string{".bindataignore"}
+	c.Output = filepath.Join(mod, "bindata.go")
+	c.Debug = true
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := goCommand(t, mod, nil, "run", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	expected := `[web/a.txt web/b.txt]
+dir [a.txt b.txt] <nil>
+[web/b.txt web/new/c.txt]
+dir [b.txt new] <nil>
+"new\n" <nil>
+Asset web/a.txt not found
+Asset web/b.txt not found
+dir [c.txt] <nil>
+<nil>
+"new\n" <nil>
+`
+	if string(out) != expected {
+		t.Errorf("expected output\n%s\ngot\n%s", expected, out)
+	}
+}
+
+const dynamicDevMain = `package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+var rootDir = ".."
+
+func main() {
+	names := AssetNames()
+	sort.Strings(names)
+	fmt.Println(names)
+	dir, err := AssetDir("in")
+	sort.Strings(dir)
+	fmt.Println("dir", dir, err)
+	_, err = Asset("in/b.bak")
+	fmt.Println(err)
+}
+`
+
+func TestTranslateDynamicDev(t *testing.T) {
+	dir, mod := newTestModule(t, dynamicDevMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	writeTestFiles(t, map[string]string{
+		filepath.Join(in, "a.txt"): "a\n",
+		filepath.Join(in, "b.bak"): "b\n",
+	})
+
+	// The ignore patterns match the paths the files are found at, not
+	// their names relative to rootDir.
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Prefix: dir}}
+	c.Ignore = []*regexp.Regexp{regexp.MustCompile("^" + regexp.QuoteMeta(filepath.Join(in, "b.bak")) + "$")}
+	c.Output = filepath.Join(mod, "bindata.go")
+	c.Debug = true
+	c.Dev = true
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := goCommand(t, mod, nil, "run", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	expected := `[in/a.txt]
+dir [a.txt] <nil>
+Asset in/b.bak not found
+`
+	if string(out) != expected {
+		t.Errorf("expected output\n%s\ngot\n%s", expected, out)
+	}
+}
+
+const dynamicLinksMain = `package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+func main() {
+	names := AssetNames()
+	sort.Strings(names)
+	fmt.Println(names)
+	for _, name := range []string{"dirlink/b.txt", "sub/b.txt", "sub/loop/b.txt"} {
+		_, err := Asset(name)
+		fmt.Println(name, err)
+	}
+}
+`
+
+func TestTranslateDynamicLinks(t *testing.T) {
+	dir, mod := newTestModule(t, dynamicLinksMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	writeTestFiles(t, map[string]string{filepath.Join(in, "sub", "b.txt"): "b\n"})
+	if err := os.Symlink("sub", filepath.Join(in, "dirlink")); err != nil {
+		t.Skip(err)
+	}
+	if err := os.Symlink(".", filepath.Join(in, "sub", "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory reached through a link is still found at its own path,
+	// only links looping back into a directory are dropped.
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true}}
+	c.Prefix = in
+	c.Output = filepath.Join(mod, "bindata.go")
+	c.Debug = true
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := goCommand(t, mod, nil, "run", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	expected := `[dirlink/b.txt sub/b.txt]
+dirlink/b.txt <nil>
+sub/b.txt <nil>
+sub/loop/b.txt Asset sub/loop/b.txt not found
+`
+	if string(out) != expected {
+		t.Errorf("expected output\n%s\ngot\n%s", expected, out)
+	}
+}
+
+const dynamicMetaMain = `package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
+func show() {
+	names := AssetNames()
+	sort.Strings(names)
+	fmt.Println(names)
+	dir, err := AssetDir("")
+	sort.Strings(dir)
+	fmt.Println("dir", dir, err)
+}
+
+func main() {
+	show()
+	ioutil.WriteFile("../in/b.css", []byte("b\n"), 0644)
+	ioutil.WriteFile("../in/b.css.meta.json", []byte("{}\n"), 0644)
+	show()
+	_, err := Asset("b.css.meta.json")
+	fmt.Println(err)
+}
+`
+
+func TestTranslateDynamicSidecars(t *testing.T) {
+	dir, mod := newTestModule(t, dynamicMetaMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	writeTestFiles(t, map[string]string{
+		filepath.Join(in, "a.css"):             "a\n",
+		filepath.Join(in, "a.css.meta.json"):   `{"tags": ["css"]}`,
+		filepath.Join(in, ".bindatameta.json"): `[{"pattern": "*.css", "tags": ["style"]}]`,
+		filepath.Join(in, "x.meta.json"):       "{}\n",
+	})
+
+	// Sidecar files found at runtime are no assets either, only a file
+	// named like one without an asset next to it is.
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true}}
+	c.Prefix = in
+	c.Output = filepath.Join(mod, "bindata.go")
+	c.Debug = true
+	c.AssetMeta = true
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := goCommand(t, mod, nil, "run", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	expected := `[a.css x.meta.json]
+dir [a.css x.meta.json] <nil>
+[a.css b.css x.meta.json]
+dir [a.css b.css x.meta.json] <nil>
+Asset b.css.meta.json not found
+`
+	if string(out) != expected {
+		t.Errorf("expected output\n%s\ngot\n%s", expected, out)
+	}
+}
+
+const dynamicInfoMain = `package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+	os.MkdirAll("../in/new", 0755)
+	ioutil.WriteFile("../in/new/b.sh", []byte("b\n"), 0644)
+	ioutil.WriteFile("../in/new/c.txt", []byte("c\n"), 0644)
+	for _, name := range []string{"a.txt", "new/b.sh", "new/c.txt", "new"} {
+		fi, err := AssetInfo(name)
+		if err != nil {
+			fmt.Println(name, err)
+			continue
+		}
+		fmt.Println(name, fi.Size(), fi.Mode(), fi.ModTime().Unix())
+	}
+}
+`
+
+func TestTranslateDynamicInfo(t *testing.T) {
+	dir, mod := newTestModule(t, dynamicInfoMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	rules := []MetadataRule{{Pattern: "*.sh", Mode: 0755, ModTime: 1600000000}, {Pattern: "**", ModTimeSource: ModTimeGit}}
+	tests := map[string]struct {
+		configure func(c *Config)
+		expected  string
+	}{
+		// Times from git are not known for new files, they keep the
+		// time of the earlier rule.
+		"overrides": {func(c *Config) {
+			c.Mode = 0600
+			c.ModTime = 1500000000
+			c.MetadataRules = rules
+		}, `a.txt 2 -rw------- 1500000000
+new/b.sh 2 -rwxr-xr-x 1600000000
+new/c.txt 2 -rw------- 1500000000
+new 0 drwx------ 1500000000
+`},
+		"nometadata": {func(c *Config) {
+			c.NoMetadata = true
+		}, `a.txt 0 ---------- 0
+new/b.sh 0 ---------- 0
+new/c.txt 0 ---------- 0
+new 0 d--------- 0
+`},
+	}
+	for name, test := range tests {
+		os.RemoveAll(in)
+		writeTestFiles(t, map[string]string{filepath.Join(in, "a.txt"): "a\n"})
+
+		c := NewConfig()
+		c.Input = []InputConfig{{Path: in, Recursive: true}}
+		c.Prefix = in
+		c.Output = filepath.Join(mod, "bindata.go")
+		c.Debug = true
+		test.configure(c)
+		if err := Translate(c); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		out, err := goCommand(t, mod, nil, "run", ".").CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: run: %v\n%s", name, err, out)
+		}
+		if string(out) != test.expected {
+			t.Errorf("%s: expected output\n%s\ngot\n%s", name, test.expected, out)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// legacyPath returns the path of the release output for older toolchains
+// written next to the given output file in Embed mode.
+func legacyPath(output string) string {
+	return strings.TrimSuffix(output, ".go") + "_legacy.go"
+}
+
+// joinTags returns the build tags which hold when both the given tags and
+// the single tag hold.
+func joinTags(tags, tag string) string {
+	options := strings.Fields(tags)
+	if len(options) == 0 {
+		return tag
+	}
+
+	for i := range options {
+		options[i] += "," + tag
+	}
+	return strings.Join(options, " ")
+}
+
+// translateEmbed writes the output of Embed mode, along with the release
+// output guarded by a build tag for older toolchains unless NoLegacy is set.
+func translateEmbed(c *Config, toc, dirs []Asset) error {
+	legacy := *c
+	legacy.Embed = false
+	legacy.Output = legacyPath(c.Output)
+	legacy.Tags = joinTags(c.Tags, "!go1.16")
+	if len(c.Cache) > 0 {
+		legacy.Cache = filepath.Join(c.Cache, "legacy")
+	}
+	legacyToc := append([]Asset(nil), toc...)
+
+	embed := *c
+	embed.Tags = joinTags(c.Tags, "go1.16")
+	tableFuncs(toc, "_bindataEmbedded")
+
+	err := writeGoFile(c.Output, func(w io.Writer) error {
+		return writeCode(w, &embed, toc, dirs, false, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = removeStaleShards(c.Output, []string{c.Output})
+	if err != nil {
+		return err
+	}
+
+	if c.layered() {
+		err = writeGoFile(layersPath(c.Output), func(w io.Writer) error {
+			return writeLayers(w, &embed)
+		})
+	} else {
+		err = removeGenerated(layersPath(c.Output))
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.NoLegacy {
+		return removeLegacy(legacy.Output)
+	}
+	return generate(&legacy, legacyToc, dirs)
+}
+
+// removeLegacy removes the release output for older toolchains and its
+// shards left over from previous runs, if they were generated by go-bindata.
+func removeLegacy(path string) error {
+	if err := removeGenerated(path); err != nil {
+		return err
+	}
+	if err := removeGenerated(layersPath(path)); err != nil {
+		return err
+	}
+	return removeStaleShards(path, nil)
+}
+
+// removeGenerated removes the file at path, if it was generated by
+// go-bindata.
+func removeGenerated(path string) error {
+	if isGenerated(path) {
+		return os.Remove(path)
+	}
+	return nil
+}
+
+// isGenerated reports whether the file at path starts with the header of
+// the code generated by go-bindata.
+func isGenerated(path string) bool {
+	line, err := firstLine(path)
+	return err == nil && strings.HasPrefix(line, "// ") && strings.Contains(line, "Code generated by go-bindata.")
+}
+
+// writeEmbed writes the release code of Embed mode. The asset files are
+// listed in //go:embed directives, and the table of embedded assets
+// records the file and the info of each asset. Aliases share the file of
+// their target, which is embedded once.
+func writeEmbed(w io.Writer, c *Config, toc []Asset) error {
+	dir, err := filepath.Abs(filepath.Dir(c.Output))
+	if err != nil {
+		return err
+	}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = real
+	}
+
+	index := make(map[string]int, len(toc))
+	paths := make([]string, len(toc))
+	for i := range toc {
+		index[toc[i].Func] = i
+		if len(toc[i].Alias) > 0 {
+			continue
+		}
+		if paths[i], err = embedPath(dir, &toc[i]); err != nil {
+			return err
+		}
+	}
+	for i := range toc {
+		if j, ok := index[toc[i].Alias]; ok {
+			paths[i] = paths[j]
+		}
+	}
+
+	imports := []string{"embed", "fmt", "io/ioutil", "os", "path/filepath", "strings", "time"}
+	if c.HttpFileSystem {
+		imports = append(imports, "bytes", "net/http")
+	}
+
+	_, err = fmt.Fprintf(w, `import (
+	"%s"
+)
+
+`, strings.Join(imports, "\"\n\t\""))
+	if err != nil {
+		return err
+	}
+
+	for i := range toc {
+		if len(toc[i].Alias) > 0 || len(paths[i]) == 0 {
+			continue
+		}
+
+		directive := paths[i]
+		if strings.Contains(directive, " ") {
+			directive = fmt.Sprintf("%q", directive)
+		}
+		if _, err = fmt.Fprintf(w, "//go:embed %s\n", directive); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, `var _bindataEmbedFS embed.FS
+
+// bindataEmbedded locates the embedded file of an asset, along with its
+// info. Assets without contents have no file.
+type bindataEmbedded struct {
+	path    string
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime int64
+	link    string
+}
+
+func (e *bindataEmbedded) load() (*asset, error) {
+	var bytes []byte
+	if len(e.path) > 0 {
+		var err error
+		bytes, err = _bindataEmbedFS.ReadFile(e.path)
+		if err != nil {
+			return nil, fmt.Errorf("read %%q: %%v", e.name, err)
+		}
+	}
+
+	info := bindataFileInfo{name: e.name, size: e.size, mode: e.mode, modTime: time.Unix(e.modTime, 0)}
+	a := &asset{bytes: bytes, info: info, link: e.link}
+	return a, nil
+}
+
+`)
+	if err != nil {
+		return err
+	}
+
+	if err = header_release_common(w); err != nil {
+		return err
+	}
+
+	if err = writeAssetFS(w, c); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, `// _bindataEmbedded is a table, holding the embedded file and info of each asset.
+var _bindataEmbedded = [...]bindataEmbedded{
+`)
+	if err != nil {
+		return err
+	}
+
+	for i := range toc {
+		size, mode, modTime, err := toc[i].metadata(c)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w, "\t{path: %q, name: %q, size: %d, mode: os.FileMode(%d), modTime: %d, link: %q},\n",
+			paths[i], toc[i].Name, size, mode, modTime, toc[i].Link)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeTOCFooter(w)
+}
+
+// embedPath returns the slash separated path of the file holding the
+// contents of the asset, relative to the directory of the output file, as
+// used in //go:embed directives. Links are resolved, since the toolchain
+// does not embed them. It returns an empty path for assets without
+// contents, such as dangling preserved links.
+func embedPath(dir string, asset *Asset) (string, error) {
+	path, err := filepath.EvalSymlinks(asset.Path)
+	if err != nil {
+		if len(asset.Link) > 0 {
+			return "", nil
+		}
+		return "", err
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !fi.Mode().IsRegular() {
+		return "", nil
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("cannot embed %s: not in the directory of the output file %s", asset.Path, dir)
+	}
+
+	rel = filepath.ToSlash(rel)
+	if !validEmbedPath(rel) {
+		return "", fmt.Errorf("cannot embed %s: file name not supported by go:embed", asset.Path)
+	}
+
+	return rel, nil
+}
+
+// validEmbedPath reports whether the toolchain accepts the relative path
+// as a //go:embed pattern matching just the file itself. Characters with a
+// meaning in patterns, and characters not allowed in module zip files, are
+// rejected.
+func validEmbedPath(path string) bool {
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return false
+		}
+	}
+
+	for _, r := range path {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-./_~ !#$%&()+,;=@^{}", r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,216 @@
+package bindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// printInfoMain prints the contents and info of all assets of the
+// generated code, and the children of the directories holding them.
+const printInfoMain = `package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+func main() {
+	names := AssetNames()
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := Asset(name)
+		if err != nil {
+			panic(err)
+		}
+		fi, err := AssetInfo(name)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%s=%q %s %d %v %d\n", name, data, fi.Name(), fi.Size(), fi.Mode(), fi.ModTime().Unix())
+
+		dir := path.Dir(name)
+		if dir == "." {
+			dir = ""
+		}
+		children, err := AssetDir(dir)
+		if err != nil {
+			panic(err)
+		}
+		sort.Strings(children)
+		fmt.Printf("%q: %q\n", dir, children)
+	}
+}
+`
+
+func TestJoinTags(t *testing.T) {
+	tests := []struct {
+		tags, tag, expected string
+	}{
+		{"", "go1.16", "go1.16"},
+		{"foo", "go1.16", "foo,go1.16"},
+		{"foo bar,baz", "!go1.16", "foo,!go1.16 bar,baz,!go1.16"},
+	}
+	for _, test := range tests {
+		if got := joinTags(test.tags, test.tag); got != test.expected {
+			t.Errorf("joinTags(%q, %q): expected %q, got %q", test.tags, test.tag, test.expected, got)
+		}
+	}
+}
+
+func TestTranslateEmbed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "static")
+	if err := os.MkdirAll(filepath.Join(in, "css"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{"index.html": "<p>", "copy.html": "<p>", "css/a b.css": "p {}"}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(in, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true}}
+	c.Prefix = in
+	c.Output = filepath.Join(dir, "bindata.go")
+	c.Embed = true
+	c.ModTime = 1
+
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(c.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+
+	for _, s := range []string{
+		"// +build go1.16\n",
+		"//go:embed static/copy.html\n//go:embed \"static/css/a b.css\"\nvar _bindataEmbedFS embed.FS",
+		`{path: "static/copy.html", name: "index.html", size: 3, mode: os.FileMode(420), modTime: 1, link: ""},`,
+		`"css/a b.css": _bindataEmbedded[1].load,`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected output to contain %q", s)
+		}
+	}
+	if strings.Contains(out, "//go:embed static/index.html") {
+		t.Errorf("expected identical files to be embedded once")
+	}
+
+	legacy := filepath.Join(dir, "bindata_legacy.go")
+	data, err = ioutil.ReadFile(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "// +build !go1.16\n") || !strings.Contains(string(data), "func bindataRead(") {
+		t.Errorf("expected release output for older toolchains")
+	}
+
+	c.NoLegacy = true
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", legacy, err)
+	}
+
+	c.Output = filepath.Join(dir, "sub", "bindata.go")
+	if err := Translate(c); err == nil || !strings.Contains(err.Error(), "not in the directory of the output file") {
+		t.Errorf("expected error for files outside of the output directory, got %v", err)
+	}
+}
+
+func TestBuildEmbed(t *testing.T) {
+	dir, mod := newTestModule(t, printInfoMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(mod, "static")
+	writeTestFiles(t, map[string]string{
+		filepath.Join(mod, "go.mod"):            "module x\n\ngo 1.16\n",
+		filepath.Join(in, "index.html"):         "<p>",
+		filepath.Join(in, "copy.html"):          "<p>",
+		filepath.Join(in, "css", "a b.css"):     "p {}",
+		filepath.Join(in, "css", "sub", "x.js"): "x",
+	})
+
+	translate := func(output string, embed bool) {
+		c := NewConfig()
+		c.Input = []InputConfig{{Path: in, Recursive: true}}
+		c.Prefix = in
+		c.Output = output
+		c.Embed = embed
+		c.ModTime = 1
+		for _, r := range []string{"css/**:mode=0600,mtime=2", "x.js:mode=0755"} {
+			rule, err := ParseMetadataRule(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.MetadataRules = append(c.MetadataRules, rule)
+		}
+		if err := Translate(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(mod string) string {
+		out, err := goCommand(t, mod, nil, "run", ".").CombinedOutput()
+		if err != nil {
+			t.Fatalf("run: %v\n%s", err, out)
+		}
+		return string(out)
+	}
+
+	// The release output is the reference.
+	release := filepath.Join(dir, "release")
+	writeTestFiles(t, map[string]string{
+		filepath.Join(release, "go.mod"):  "module x\n\ngo 1.12\n",
+		filepath.Join(release, "main.go"): printInfoMain,
+	})
+	translate(filepath.Join(release, "bindata.go"), false)
+	expected := run(release)
+	for _, line := range []string{
+		"index.html=\"<p>\" index.html 3 -rw-r--r-- 1\n",
+		"css/a b.css=\"p {}\" css/a b.css 4 -rw------- 2\n",
+		"css/sub/x.js=\"x\" css/sub/x.js 1 -rwxr-xr-x 2\n",
+		"\"css\": [\"a b.css\" \"sub\"]\n",
+	} {
+		if !strings.Contains(expected, line) {
+			t.Errorf("expected output to contain %q, got\n%s", line, expected)
+		}
+	}
+
+	translate(filepath.Join(mod, "bindata.go"), true)
+	if out := run(mod); out != expected {
+		t.Errorf("expected embedded assets to print\n%s\ngot\n%s", expected, out)
+	}
+
+	// The legacy output is built in a module of its own, without its
+	// build constraint, as it would be by toolchains before go1.16.
+	legacy := filepath.Join(dir, "legacy")
+	data, err := ioutil.ReadFile(legacyPath(filepath.Join(mod, "bindata.go")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "// +build !go1.16\n") {
+		t.Fatalf("expected legacy output to be built by older toolchains")
+	}
+	writeTestFiles(t, map[string]string{
+		filepath.Join(legacy, "go.mod"):     "module x\n\ngo 1.12\n",
+		filepath.Join(legacy, "main.go"):    printInfoMain,
+		filepath.Join(legacy, "bindata.go"): strings.NewReplacer("//go:build !go1.16\n", "", "// +build !go1.16\n", "").Replace(string(data)),
+	})
+	if out := run(legacy); out != expected {
+		t.Errorf("expected legacy assets to print\n%s\ngot\n%s", expected, out)
+	}
+}
@@ -14,6 +14,7 @@ func writeAssetFS(w io.Writer, c *Config) error {
 type assetFile struct {
 	*bytes.Reader
 	name            string
+	isDir           bool
 	childInfos      []os.FileInfo
 	childInfoOffset int
 }
@@ -42,7 +43,7 @@ func (f *assetOperator) Open(name string) (http.File, error) {
 				childInfos = append(childInfos, newDirFileInfo(childPath))
 			}
 		}
-		return &assetFile{name: name, childInfos: childInfos}, nil
+		return &assetFile{name: name, isDir: true, childInfos: childInfos}, nil
 	} else {
 		// If the error is not found, return an error that will
 		// result in a 404 error. Otherwise the server returns
@@ -61,7 +62,7 @@ func (f *assetFile) Close() error {
 
 // Readdir read dir's children file info
 func (f *assetFile) Readdir(count int) ([]os.FileInfo, error) {
-	if len(f.childInfos) == 0 {
+	if !f.isDir {
 		return nil, os.ErrNotExist
 	}
 	if count <= 0 {
@@ -77,13 +78,17 @@ func (f *assetFile) Readdir(count int) ([]os.FileInfo, error) {
 
 // Stat read file info from asset item
 func (f *assetFile) Stat() (os.FileInfo, error) {
-	if len(f.childInfos) != 0 {
+	if f.isDir {
+		if info, err := AssetInfo(f.name); err == nil {
+			return info, nil
+		}
 		return newDirFileInfo(f.name), nil
 	}
 	return AssetInfo(f.name)
 }
 
-// newDirFileInfo return default dir file info
+// newDirFileInfo return default dir file info for directories
+// without recorded metadata
 func newDirFileInfo(name string) os.FileInfo {
 	return &bindataFileInfo{
 		name:    name,
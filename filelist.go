@@ -0,0 +1,84 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadFileList reads a list of input files, one path per line or separated
+// by NUL bytes as produced by `find -print0` or `git ls-files -z`. A path
+// may be followed by =name to store it under that exact asset name instead
+// of the name derived from the prefix, which rename rules leave alone. The
+// line is split at the last `=`, unless the whole line names an existing
+// file. A tab may separate the path and the name instead, in which case
+// the path ends at the first tab and may contain `=`. Empty lines are
+// skipped.
+//
+// The files are grouped into one input per directory, in the order the
+// directories are first listed, so that the ignore files of each directory
+// are read once. A listed directory is an input of its own, which is not
+// recursive. The inputs pass through the same ignore patterns, rename rules
+// and metadata handling as directory inputs.
+func ReadFileList(r io.Reader) ([]InputConfig, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sep := "\n"
+	if bytes.IndexByte(data, 0) >= 0 {
+		sep = "\x00"
+	}
+
+	var inputs []InputConfig
+	dirs := make(map[string]int)
+	for _, line := range strings.Split(string(data), sep) {
+		if sep == "\n" {
+			line = strings.TrimSuffix(line, "\r")
+		}
+		if len(line) == 0 {
+			continue
+		}
+
+		var file InputFile
+		if i := strings.IndexByte(line, '\t'); i >= 0 {
+			file.AssetName = line[i+1:]
+			line = line[:i]
+		} else if i := strings.LastIndexByte(line, '='); i >= 0 && !exists(line) {
+			file.AssetName = line[i+1:]
+			line = line[:i]
+		}
+
+		path := filepath.Clean(line)
+		if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+			inputs = append(inputs, InputConfig{Path: path})
+			continue
+		}
+
+		dir := filepath.Dir(path)
+		i, ok := dirs[dir]
+		if !ok {
+			i = len(inputs)
+			dirs[dir] = i
+			inputs = append(inputs, InputConfig{Path: dir})
+		}
+		file.Name = filepath.Base(path)
+		inputs[i].Files = append(inputs[i].Files, file)
+	}
+
+	return inputs, nil
+}
+
+// exists reports whether a file or directory exists at path.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
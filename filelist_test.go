@@ -0,0 +1,81 @@
+package bindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestReadFileList(t *testing.T) {
+	rename, err := ParseRenameRule(`\.asset$=.txt`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, list := range []string{
+		"testdata/in/a/test.asset\tstatic/a.asset\r\n\ntestdata/in/test.asset\ntestdata/in/b\ntestdata/in/./c/../a/../test.asset\tb.asset\n",
+		"testdata/in/a/test.asset\tstatic/a.asset\x00testdata/in/test.asset\x00testdata/in/b\x00testdata/in/./c/../a/../test.asset\tb.asset\x00",
+		"testdata/in/a/test.asset=static/a.asset\ntestdata/in/test.asset\ntestdata/in/b\ntestdata/in/./c/../a/../test.asset=b.asset\n",
+	} {
+		inputs, err := ReadFileList(strings.NewReader(list))
+		if err != nil {
+			t.Fatalf("expected to be no error: %+v", err)
+		}
+		if len(inputs) != 3 || len(inputs[1].Files) != 2 || inputs[1].Path != "testdata/in" || inputs[2].Path != "testdata/in/b" || len(inputs[2].Files) != 0 {
+			t.Fatalf("Expected files grouped by directory, got %+v", inputs)
+		}
+
+		var toc []Asset
+		var dirs []Asset
+		var knownFuncs = make(map[string]int)
+		var visitedPaths = make(map[string]bool)
+		for _, input := range inputs[:2] {
+			input.Prefix = "testdata"
+			input.Rename = []RenameRule{rename}
+			err = findFiles(input.Path, input, &toc, &dirs, []*regexp.Regexp{}, nil, knownFuncs, visitedPaths)
+			if err != nil {
+				t.Errorf("expected to be no error: %+v", err)
+			}
+		}
+
+		// Explicit names are kept as given.
+		if len(toc) != 3 || toc[0].Name != "static/a.asset" || toc[1].Name != "in/test.txt" || toc[2].Name != "b.asset" {
+			t.Errorf("Unexpected assets: %v", toc)
+		}
+		if len(dirs) != 0 {
+			t.Errorf("Unexpected directories: %v", dirs)
+		}
+	}
+}
+
+func TestReadFileListEquals(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "a=b")
+	writeTestFiles(t, map[string]string{filepath.Join(in, "c.txt"): "c\n", filepath.Join(in, "d=e.txt"): "d\n"})
+
+	// A line naming an existing file is a path, otherwise the last = maps
+	// the path to a name. A tab maps paths which contain = themselves.
+	list := in + "/c.txt\n" + in + "/c.txt=x.txt\n" + in + "/d=e.txt\ty=z.txt\n" + in + "/f.txt=w.txt\n"
+	inputs, err := ReadFileList(strings.NewReader(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []InputConfig{{Path: in, Files: []InputFile{
+		{Name: "c.txt"},
+		{Name: "c.txt", AssetName: "x.txt"},
+		{Name: "d=e.txt", AssetName: "y=z.txt"},
+		{Name: "f.txt", AssetName: "w.txt"},
+	}}}
+	if !reflect.DeepEqual(inputs, expected) {
+		t.Errorf("expected %+v, got %+v", expected, inputs)
+	}
+}
@@ -0,0 +1,22 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arpabet/go-bindata"
+)
+
+// appendArchive appends an archive written with -archive to a built
+// executable, replacing an archive appended before.
+func appendArchive(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s append <executable> <archive>", os.Args[0])
+	}
+
+	return bindata.AppendArchive(args[0], args[1])
+}
@@ -0,0 +1,184 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/arpabet/go-bindata"
+)
+
+// ls lists the assets of a generated file with their recorded info.
+func ls(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s ls <bindata.go>", os.Args[0])
+	}
+
+	g, err := bindata.ReadGenerated(args[0])
+	if err != nil {
+		return err
+	}
+
+	width := 1
+	for _, asset := range g.Assets {
+		if n := len(fmt.Sprint(asset.Size)); n > width {
+			width = n
+		}
+	}
+
+	for _, asset := range g.Assets {
+		name := asset.Name
+		if len(asset.Link) > 0 {
+			name += " -> " + asset.Link
+		}
+		fmt.Printf("%v %*d %s %s\n", asset.Mode, width, asset.Size, formatTime(asset.ModTime), name)
+	}
+	return nil
+}
+
+// cat writes the contents of an asset of a generated file to stdout.
+func cat(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s cat <bindata.go> <asset>", os.Args[0])
+	}
+
+	g, err := bindata.ReadGenerated(args[0])
+	if err != nil {
+		return err
+	}
+
+	asset := g.Asset(args[1])
+	if asset == nil {
+		return fmt.Errorf("asset %q not found in %s", args[1], args[0])
+	}
+	if asset.Archived && len(asset.Link) == 0 {
+		return fmt.Errorf("the data of asset %q is kept in an archive, see extract-binary", args[1])
+	}
+
+	_, err = os.Stdout.Write(asset.Data)
+	return err
+}
+
+// extract restores the assets of a generated file to a directory.
+func extract(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s extract <bindata.go> <directory>", os.Args[0])
+	}
+
+	g, err := bindata.ReadGenerated(args[0])
+	if err != nil {
+		return err
+	}
+
+	return g.Restore(args[1])
+}
+
+// extractBinary restores the assets found in an executable to a
+// directory. Only the assets of builds with -packed, -solid, -asm or
+// -archive are found.
+func extractBinary(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s extract-binary <executable> <directory>", os.Args[0])
+	}
+
+	g, err := bindata.ReadBinary(args[0])
+	if err != nil {
+		return err
+	}
+
+	return g.Restore(args[1])
+}
+
+// diff reports the assets added, removed and modified between two generated
+// files. It exits with status 1 when they differ.
+func diff(args []string) error {
+	var unified bool
+	var context int
+
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("Usage: %s diff [options] <old.go> <new.go>\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.BoolVar(&unified, "u", false, "Print a unified diff of the contents of modified text assets.")
+	fs.IntVar(&context, "U", 3, "Number of lines of context of unified diffs.")
+
+	files := parseFlags(fs, args)
+	if len(files) != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	a, err := bindata.ReadGenerated(files[0])
+	if err != nil {
+		return err
+	}
+	b, err := bindata.ReadGenerated(files[1])
+	if err != nil {
+		return err
+	}
+
+	changes := bindata.DiffGenerated(a, b)
+
+	var added, removed, modified int
+	var delta int64
+	for i := range changes {
+		ch := &changes[i]
+		delta += ch.SizeDelta()
+
+		switch {
+		case ch.Old == nil:
+			added++
+			fmt.Printf("added     %s (%d bytes)\n", ch.Name, len(ch.New.Data))
+			continue
+		case ch.New == nil:
+			removed++
+			fmt.Printf("removed   %s (%d bytes)\n", ch.Name, len(ch.Old.Data))
+			continue
+		}
+
+		modified++
+		fmt.Printf("modified  %s\n", ch.Name)
+		if ch.ContentChanged() {
+			fmt.Printf("          contents %d -> %d bytes (%+d)\n", len(ch.Old.Data), len(ch.New.Data), ch.SizeDelta())
+		}
+		if ch.Old.Mode != ch.New.Mode {
+			fmt.Printf("          mode %v -> %v\n", ch.Old.Mode, ch.New.Mode)
+		}
+		if ch.Old.ModTime != ch.New.ModTime {
+			fmt.Printf("          mtime %s -> %s\n", formatTime(ch.Old.ModTime), formatTime(ch.New.ModTime))
+		}
+		if ch.Old.Link != ch.New.Link {
+			fmt.Printf("          link %q -> %q\n", ch.Old.Link, ch.New.Link)
+		}
+
+		if unified && ch.ContentChanged() {
+			if !ch.IsText() {
+				fmt.Printf("          binary contents differ\n")
+				continue
+			}
+			err = bindata.WriteUnifiedDiff(os.Stdout, "a/"+ch.Name, "b/"+ch.Name, ch.Old.Data, ch.New.Data, context)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	fmt.Printf("%d added, %d removed, %d modified, %+d bytes\n", added, removed, modified, delta)
+	os.Exit(1)
+	return nil
+}
+
+// formatTime formats a unix timestamp in UTC.
+func formatTime(t int64) string {
+	return time.Unix(t, 0).UTC().Format("2006-01-02 15:04:05")
+}
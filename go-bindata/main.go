@@ -15,9 +15,26 @@ import (
 	"github.com/arpabet/go-bindata"
 )
 
+// commands holds the subcommands, which are run with the remaining
+// arguments.
+var commands = map[string]func(args []string) error{
+	"migrate": migrate,
+	"ls":      ls,
+	"cat":     cat,
+	"extract": extract,
+	"diff":    diff,
+	"append":  appendArchive,
+
+	"extract-binary": extractBinary,
+}
+
 func main() {
-	cfg := parseArgs()
-	err := bindata.Translate(cfg)
+	var err error
+	if cmd := command(os.Args[1:]); cmd != nil {
+		err = cmd(os.Args[2:])
+	} else {
+		err = bindata.Translate(parseArgs())
+	}
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "bindata: %v\n", err)
@@ -25,18 +42,39 @@ func main() {
 	}
 }
 
+// command returns the subcommand named by the first argument, if any. An
+// existing file or directory of that name is an input, as it was before
+// there were subcommands.
+func command(args []string) func(args []string) error {
+	if len(args) == 0 || commands[args[0]] == nil {
+		return nil
+	}
+	if _, err := os.Lstat(args[0]); err == nil {
+		return nil
+	}
+	return commands[args[0]]
+}
+
 // parseArgs create s a new, filled configuration instance
 // by reading and parsing command line options.
 //
 // This function exits the program with an error, if
 // any of the command line options are incorrect.
 func parseArgs() *bindata.Config {
-	var version bool
+	var version, verbose bool
 
 	c := bindata.NewConfig()
 
 	flag.Usage = func() {
-		fmt.Printf("Usage: %s [options] <input directories>\n\n", os.Args[0])
+		fmt.Printf("Usage: %s [options] <input directories>\n", os.Args[0])
+		fmt.Printf("       %s migrate [options] <bindata.go>\n", os.Args[0])
+		fmt.Printf("       %s ls <bindata.go>\n", os.Args[0])
+		fmt.Printf("       %s cat <bindata.go> <asset>\n", os.Args[0])
+		fmt.Printf("       %s extract <bindata.go> <directory>\n", os.Args[0])
+		fmt.Printf("       %s diff [options] <old.go> <new.go>\n", os.Args[0])
+		fmt.Printf("       %s append <executable> <archive>\n", os.Args[0])
+		fmt.Printf("       %s extract-binary <executable> <directory>\n\n", os.Args[0])
+		fmt.Printf("A first argument naming an existing file or directory is an input, not a command.\n\n")
 		flag.PrintDefaults()
 	}
 
@@ -47,19 +85,74 @@ func parseArgs() *bindata.Config {
 	flag.StringVar(&c.Package, "pkg", c.Package, "Package name to use in the generated code.")
 	flag.BoolVar(&c.NoMemCopy, "nomemcopy", c.NoMemCopy, "Use a .rodata hack to get rid of unnecessary memcopies. Refer to the documentation to see what implications this carries.")
 	flag.BoolVar(&c.NoCompress, "nocompress", c.NoCompress, "Assets will *not* be GZIP compressed when this flag is specified.")
+	flag.BoolVar(&c.NoDedup, "nodedup", c.NoDedup, "Store the contents of every file, instead of storing identical contents once.")
+	flag.BoolVar(&c.Packed, "packed", c.Packed, "Store the data of all assets in one string with a table of offsets, instead of a variable and functions per asset.")
+	flag.Int64Var(&c.Solid, "solid", c.Solid, "Optional size in bytes of groups of small files compressed together as one stream. Implies -packed.")
+	flag.BoolVar(&c.Embed, "embed", c.Embed, "Generate code embedding the files with //go:embed (Go 1.16+), plus the classic output in a _legacy.go file for older toolchains. The files must be in the directory of the output file.")
+	flag.BoolVar(&c.NoLegacy, "nolegacy", c.NoLegacy, "Do not write the classic output for older toolchains with -embed.")
+	flag.StringVar(&c.Archive, "archive", c.Archive, "Optional file to write the data of all assets to, as an archive to append to the built executable with the append command, instead of compiling the data in.")
+	flag.StringVar(&c.PackFile, "pack", c.PackFile, "Optional file to write the assets to as an asset pack, to be loaded at runtime with LoadPack, instead of generating code.")
+	flag.BoolVar(&c.LoadPacks, "loadpacks", c.LoadPacks, "Generate LoadPack and UnloadPack, which layer asset packs over the embedded assets, in a _layers.go file next to the output file.")
+	flag.BoolVar(&c.Overlay, "overlay", c.Overlay, "Generate SetOverlay, which sets a directory whose files take precedence over the embedded assets at runtime.")
+	flag.StringVar(&c.OverlayEnv, "overlayenv", c.OverlayEnv, "Optional environment variable from which the overlay directory is set at startup. Implies -overlay.")
+	flag.BoolVar(&c.Assembly, "asm", c.Assembly, "Write the data of all assets to a Go assembly file next to the output file, which the compiler does not need to parse.")
 	flag.BoolVar(&c.NoUnpack, "nounpack", c.NoUnpack, "Assets will *not* be uncompressed when this flag is specified.")
 	flag.BoolVar(&c.NoMetadata, "nometadata", c.NoMetadata, "Assets will not preserve size, mode, and modtime info.")
+	flag.BoolVar(&c.AssetMeta, "meta", c.AssetMeta, "Read metadata and tags from .meta.json sidecar and .bindatameta.json files and generate AssetMeta and AssetsWithTag.")
 	flag.BoolVar(&c.HttpFileSystem, "fs", c.HttpFileSystem, "Whether generate instance http.FileSystem interface code.")
 	flag.UintVar(&c.Mode, "mode", c.Mode, "Optional file mode override for all files.")
 	flag.Int64Var(&c.ModTime, "modtime", c.ModTime, "Optional modification unix timestamp override for all files.")
 	flag.StringVar(&c.Output, "o", c.Output, "Optional name of the output file to be generated.")
 	flag.BoolVar(&version, "version", false, "Displays version information.")
+	flag.BoolVar(&verbose, "v", false, "Report a summary of the conversion, such as the bytes saved by deduplication, on stderr.")
+
+	flag.Int64Var(&c.SplitSize, "split", c.SplitSize, "Optional size in bytes at which embedded data is split into further files output_data1.go, output_data2.go...")
+	flag.BoolVar(&c.SplitDirs, "splitdirs", c.SplitDirs, "Split embedded data into further files per top-level directory.")
+
+	var cache bool
+	flag.BoolVar(&cache, "cache", false, "Keep a cache in .bindata-cache next to the output file, skipping regeneration when nothing changed and reusing compressed assets of unchanged files.")
+
+	var fileList string
+	flag.StringVar(&fileList, "filelist", "", "Optional file with newline or NUL separated input files, or - for stdin. Entries may map a file to an asset name as path=name, or path<TAB>name for paths containing =.")
 
 	ignore := make([]string, 0)
 	flag.Var((*AppendSliceValue)(&ignore), "ignore", "Regex pattern to ignore")
 
+	mounts := make([]string, 0)
+	flag.Var((*AppendSliceValue)(&mounts), "mount", "Mount rule dir=name placing the files of the input directory dir under the virtual directory name, e.g. web/dist=static")
+
+	prefixes := make([]string, 0)
+	flag.Var((*AppendSliceValue)(&prefixes), "inputprefix", "Prefix rule dir=prefix stripping prefix from the names of the files of the input directory dir, instead of -prefix")
+
+	rename := make([]string, 0)
+	flag.Var((*AppendSliceValue)(&rename), "rename", "Rename rule src=dst rewriting asset names matching the regex src")
+
+	metadata := make([]string, 0)
+	flag.Var((*AppendSliceValue)(&metadata), "metadata", "Metadata rule pattern:key=value,... setting the mode (mode=0755) or modification time (mtime=unix timestamp, git or epoch) of matching assets")
+
+	encoding := c.Encoding.String()
+	flag.StringVar(&encoding, "encoding", encoding, "How to write embedded data: string, lines for wrapped strings or bytes for a wrapped byte slice.")
+	flag.IntVar(&c.Width, "width", c.Width, "Optional number of bytes of data per line for the lines and bytes encodings, or per DATA directive with -asm.")
+
+	symlinks := bindata.SymlinkFollow.String()
+	flag.StringVar(&symlinks, "symlinks", symlinks, "How to handle symbolic links: follow, skip, error or preserve.")
+
+	ignoreFiles := strings.Join(c.IgnoreFiles, ",")
+	flag.StringVar(&ignoreFiles, "ignorefiles", ignoreFiles, "Comma separated names of ignore files with gitignore syntax to honour, e.g. .gitignore,.bindataignore.")
+
 	flag.Parse()
 
+	if verbose {
+		c.Log = os.Stderr
+	}
+
+	c.IgnoreFiles = nil
+	for _, name := range strings.Split(ignoreFiles, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			c.IgnoreFiles = append(c.IgnoreFiles, name)
+		}
+	}
+
 	patterns := make([]*regexp.Regexp, 0)
 	for _, pattern := range ignore {
 		patterns = append(patterns, regexp.MustCompile(pattern))
@@ -71,17 +164,82 @@ func parseArgs() *bindata.Config {
 		os.Exit(0)
 	}
 
+	if cache {
+		c.Cache = bindata.DefaultCacheDir(c.Output)
+	}
+
 	// Make sure we have input paths.
-	if flag.NArg() == 0 {
+	if flag.NArg() == 0 && len(fileList) == 0 {
 		fmt.Fprintf(os.Stderr, "Missing <input dir>\n\n")
 		flag.Usage()
 		os.Exit(1)
 	}
 
+	for _, m := range metadata {
+		rule, err := bindata.ParseMetadataRule(m)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bindata: %v\n", err)
+			os.Exit(1)
+		}
+		c.MetadataRules = append(c.MetadataRules, rule)
+	}
+
+	symlinkPolicy, err := bindata.ParseSymlinkPolicy(symlinks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bindata: %v\n", err)
+		os.Exit(1)
+	}
+
+	c.Encoding, err = bindata.ParseEncoding(encoding)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bindata: %v\n", err)
+		os.Exit(1)
+	}
+
+	renameRules := make([]bindata.RenameRule, 0, len(rename))
+	for _, r := range rename {
+		rule, err := bindata.ParseRenameRule(r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bindata: %v\n", err)
+			os.Exit(1)
+		}
+		renameRules = append(renameRules, rule)
+	}
+
 	// Create input configurations.
 	c.Input = make([]bindata.InputConfig, flag.NArg())
 	for i := range c.Input {
 		c.Input[i] = parseInput(flag.Arg(i))
+		c.Input[i].Rename = renameRules
+		c.Input[i].Symlinks = symlinkPolicy
+	}
+
+	listed := len(c.Input)
+	if len(fileList) > 0 {
+		inputs, err := readFileList(fileList)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bindata: %v\n", err)
+			os.Exit(1)
+		}
+		for i := range inputs {
+			inputs[i].Rename = renameRules
+			inputs[i].Symlinks = symlinkPolicy
+		}
+		c.Input = append(c.Input, inputs...)
+	}
+
+	for _, m := range mounts {
+		if err := setInputOption(c.Input, listed, m, setMount); err != nil {
+			fmt.Fprintf(os.Stderr, "bindata: invalid mount rule: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	for _, p := range prefixes {
+		if err := setInputOption(c.Input, listed, p, func(input *bindata.InputConfig, dir, prefix string) { input.Prefix = prefix }); err != nil {
+			fmt.Fprintf(os.Stderr, "bindata: invalid prefix rule: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	return c
@@ -107,3 +265,60 @@ func parseInput(path string) bindata.InputConfig {
 	}
 
 }
+
+// setInputOption parses a rule in the form `dir=value` and applies set
+// to the inputs whose path is dir. The last = separates the value, so that
+// dir itself may contain one. The inputs from listed on are read from a
+// file list, which holds an input for each directory of the listed files.
+// The rule applies to those below dir as well.
+func setInputOption(inputs []bindata.InputConfig, listed int, rule string, set func(input *bindata.InputConfig, dir, value string)) error {
+	i := strings.LastIndex(rule, "=")
+	if i < 0 {
+		return fmt.Errorf("%q: expected dir=value", rule)
+	}
+
+	dir := parseInput(rule[:i]).Path
+	found := false
+	for j := range inputs {
+		if inputs[j].Path == dir || (j >= listed && isBelow(inputs[j].Path, dir)) {
+			set(&inputs[j], dir, rule[i+1:])
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("%q: no input directory %s", rule, dir)
+	}
+	return nil
+}
+
+// setMount mounts an input matching the mount rule of dir. The inputs of a
+// file list below dir keep their path below dir under the mount.
+func setMount(input *bindata.InputConfig, dir, mount string) {
+	input.Mount = mount
+	if input.Path != dir {
+		input.Prefix = dir
+	}
+}
+
+// isBelow reports whether path lies below the directory dir.
+func isBelow(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// readFileList reads the input files listed in the named file,
+// or on standard input if the name is "-".
+func readFileList(name string) ([]bindata.InputConfig, error) {
+	if name == "-" {
+		return bindata.ReadFileList(os.Stdin)
+	}
+
+	fd, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	return bindata.ReadFileList(fd)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/arpabet/go-bindata"
+)
+
+func TestCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if command([]string{"ls", "bindata.go"}) == nil {
+		t.Errorf("expected ls to run the ls command")
+	}
+	for _, args := range [][]string{nil, {"static"}, {"-o", "ls"}} {
+		if command(args) != nil {
+			t.Errorf("%q: expected no command", args)
+		}
+	}
+
+	// An input directory named like a command is embedded.
+	if err = os.Mkdir("ls", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if command([]string{"ls", "bindata.go"}) != nil {
+		t.Errorf("expected the directory ls to be an input")
+	}
+}
+
+func TestSetInputOption(t *testing.T) {
+	dist, css := filepath.Join("web", "dist"), filepath.Join("web", "dist", "css")
+	inputs := []bindata.InputConfig{
+		{Path: dist, Recursive: true},
+		{Path: dist},
+		{Path: css},
+		{Path: "other"},
+	}
+
+	// Rules apply to the inputs of a file list below their directory, which
+	// keep their path below the mount.
+	if err := setInputOption(inputs, 1, dist+"=static", setMount); err != nil {
+		t.Fatal(err)
+	}
+	if err := setInputOption(inputs[:1], 1, css+"=static", setMount); err == nil {
+		t.Errorf("expected an error for a rule without input")
+	}
+	expected := []bindata.InputConfig{
+		{Path: dist, Recursive: true, Mount: "static"},
+		{Path: dist, Mount: "static"},
+		{Path: css, Mount: "static", Prefix: dist},
+		{Path: "other"},
+	}
+	if !reflect.DeepEqual(inputs, expected) {
+		t.Errorf("expected inputs %+v, got %+v", expected, inputs)
+	}
+}
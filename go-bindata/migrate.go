@@ -0,0 +1,67 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/arpabet/go-bindata"
+)
+
+// parseFlags parses the flags of a subcommand, which may be given before
+// and after its arguments, and returns the arguments.
+func parseFlags(fs *flag.FlagSet, args []string) []string {
+	var rest []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return rest
+		}
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// migrate runs the migrate subcommand, which replaces a generated file
+// with go:embed based output.
+func migrate(args []string) error {
+	c := bindata.NewConfig()
+	c.Output = ""
+
+	var dir string
+	var legacy, verbose bool
+
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf("Usage: %s migrate [options] <bindata.go>\n\n", os.Args[0])
+		fmt.Printf("Restores the assets of a generated file and replaces it with code\nembedding them with //go:embed.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&dir, "out", "", "Directory to restore the assets to, which must be empty. Defaults to assets next to the file.")
+	fs.StringVar(&c.Output, "o", c.Output, "Optional name of the output file. Defaults to the migrated file.")
+	fs.BoolVar(&legacy, "legacy", false, "Also write the classic output for toolchains older than Go 1.16.")
+	fs.BoolVar(&c.NoMemCopy, "nomemcopy", c.NoMemCopy, "Use a .rodata hack in the classic output. Refer to the documentation to see what implications this carries.")
+	fs.BoolVar(&c.NoCompress, "nocompress", c.NoCompress, "Assets will *not* be GZIP compressed in the classic output when this flag is specified.")
+	fs.BoolVar(&verbose, "v", false, "Report the exported functions of the file the new code lacks, along with a summary of the conversion, on stderr.")
+
+	files := parseFlags(fs, args)
+	if len(files) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if verbose {
+		c.Log = os.Stderr
+	}
+	if len(dir) == 0 {
+		dir = filepath.Join(filepath.Dir(files[0]), "assets")
+	}
+	c.NoLegacy = !legacy
+
+	return bindata.Migrate(files[0], dir, c)
+}
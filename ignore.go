@@ -0,0 +1,308 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignorePattern is a single compiled line of an ignore file.
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreFile holds the patterns read from one ignore file. The patterns
+// are matched against paths relative to base.
+type ignoreFile struct {
+	base     string // Slash separated, absolute directory of the ignore file.
+	patterns []ignorePattern
+}
+
+// ignoreRules is the set of ignore files in effect for a directory,
+// ordered from the outermost to the innermost directory. A nil
+// *ignoreRules ignores nothing.
+type ignoreRules struct {
+	names []string
+	files []*ignoreFile
+}
+
+// newIgnoreRules returns the ignore rules in effect for the given input path.
+// If the path lies inside a git work tree, the ignore files found in the
+// directories between the work tree root and the path are loaded as well, so
+// that rules from parent directories are honoured the same way git does.
+// The ignore files of the input directory itself are loaded by findFiles.
+func newIgnoreRules(names []string, path string) (*ignoreRules, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	rules := &ignoreRules{names: names}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if isWorkTreeRoot(path) {
+		return rules, nil
+	}
+
+	var parents []string
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		parents = append(parents, dir)
+		if isWorkTreeRoot(dir) {
+			break
+		}
+		if dir == filepath.Dir(dir) {
+			// Not inside a work tree: only honour the files below the input.
+			parents = parents[:1]
+			if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+				parents = nil
+			}
+			break
+		}
+	}
+
+	for i := len(parents) - 1; i >= 0; i-- {
+		rules, err = rules.load(parents[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
+
+// isWorkTreeRoot reports whether dir is the root of a git work tree. Its
+// .git is a directory, or a file pointing to the git directory in linked
+// work trees and submodules.
+func isWorkTreeRoot(dir string) bool {
+	fi, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil && (fi.IsDir() || fi.Mode().IsRegular())
+}
+
+// load returns the rules extended with the ignore files found in dir.
+func (r *ignoreRules) load(dir string) (*ignoreRules, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ignoreFile
+	for _, name := range r.names {
+		f, err := readIgnoreFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	if len(files) == 0 {
+		return r, nil
+	}
+
+	rv := &ignoreRules{names: r.names}
+	rv.files = append(rv.files, r.files...)
+	rv.files = append(rv.files, files...)
+	return rv, nil
+}
+
+// isIgnoreFile reports whether name is one of the honoured ignore files.
+// Those files control the conversion and are never embedded themselves.
+func (r *ignoreRules) isIgnoreFile(name string) bool {
+	if r == nil {
+		return false
+	}
+	for _, n := range r.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether the given file path is excluded by the rules.
+// As in git, a path is also excluded if any of its parent directories is.
+func (r *ignoreRules) match(path string, isDir bool) bool {
+	if r == nil || len(r.files) == 0 {
+		return false
+	}
+
+	path, _ = filepath.Abs(path)
+	path = filepath.ToSlash(path)
+
+	top := r.files[0].base
+	for i := len(top) + 1; i < len(path); i++ {
+		if path[i] == '/' && r.excluded(path[:i], true) {
+			return true
+		}
+	}
+
+	return r.excluded(path, isDir)
+}
+
+// excluded applies every pattern which covers path, the last match wins.
+func (r *ignoreRules) excluded(path string, isDir bool) bool {
+	ignored := false
+	for _, f := range r.files {
+		if !strings.HasPrefix(path, f.base+"/") {
+			continue
+		}
+		rel := path[len(f.base)+1:]
+		for _, p := range f.patterns {
+			if p.dirOnly && !isDir {
+				continue
+			}
+			if p.re.MatchString(rel) {
+				ignored = !p.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// readIgnoreFile reads and compiles an ignore file.
+func readIgnoreFile(filename string) (*ignoreFile, error) {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	f := &ignoreFile{base: filepath.ToSlash(filepath.Dir(filename))}
+	f.base = strings.TrimSuffix(f.base, "/")
+
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		if p, ok := parseIgnorePattern(scanner.Text()); ok {
+			f.patterns = append(f.patterns, p)
+		}
+	}
+
+	return f, scanner.Err()
+}
+
+// parseIgnorePattern compiles a single line of an ignore file, following
+// the pattern format described in gitignore(5). It returns false for blank
+// lines and comments.
+func parseIgnorePattern(line string) (ignorePattern, bool) {
+	var p ignorePattern
+
+	line = strings.TrimSuffix(line, "\r")
+
+	// Trailing spaces are ignored unless they are quoted with a backslash.
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+
+	if line == "" || line[0] == '#' {
+		return p, false
+	}
+
+	if line[0] == '!' {
+		p.negate = true
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	if line == "" {
+		return p, false
+	}
+
+	re, err := compileGlob(line)
+	if err != nil {
+		return p, false
+	}
+
+	p.re = re
+	return p, true
+}
+
+// compileGlob compiles a glob pattern matching slash separated relative
+// paths. As in gitignore(5), a pattern without a slash matches at any
+// depth, otherwise it is anchored at the start of the path.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	expr := globToRegexp(pattern)
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+
+	return regexp.Compile("^" + expr + "$")
+}
+
+// globToRegexp translates a slash separated glob pattern, which may use the
+// `**` wildcard to match across directories, into a regular expression.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**") &&
+			(i == 0 || glob[i-1] == '/') &&
+			(i+2 == len(glob) || glob[i+2] == '/'):
+			if i+2 == len(glob) {
+				sb.WriteString(".*")
+			} else {
+				sb.WriteString("(?:.*/)?")
+			}
+			i += 2
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if end == 0 {
+				// A leading ']' is part of the class.
+				end = strings.IndexByte(glob[i+2:], ']')
+				if end < 0 {
+					sb.WriteString(`\[`)
+					continue
+				}
+				end++
+				class = glob[i+1 : i+1+end]
+			}
+			if class[0] == '!' {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return sb.String()
+}
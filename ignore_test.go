@@ -0,0 +1,154 @@
+package bindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestParseIgnorePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		match   bool
+	}{
+		{"*.swp", "a.swp", false, true},
+		{"*.swp", "x/y/a.swp", false, true},
+		{"*.swp", "a.swpx", false, false},
+		{"/build", "build", true, true},
+		{"/build", "x/build", true, false},
+		{"tmp/", "x/tmp", true, true},
+		{"tmp/", "x/tmp", false, false},
+		{"doc/*.txt", "doc/a.txt", false, true},
+		{"doc/*.txt", "doc/x/a.txt", false, false},
+		{"doc/**/*.txt", "doc/x/y/a.txt", false, true},
+		{"doc/**/*.txt", "doc/a.txt", false, true},
+		{"**/cache", "a/b/cache", true, true},
+		{"a/**", "a/b/c", false, true},
+		{"file?.[ch]", "file1.c", false, true},
+		{"file?.[!ch]", "file1.c", false, false},
+		{`\#hash`, "#hash", false, true},
+		{"trailing   ", "trailing", false, true},
+	}
+
+	for _, test := range tests {
+		p, ok := parseIgnorePattern(test.pattern)
+		if !ok {
+			t.Errorf("%q: expected a pattern", test.pattern)
+			continue
+		}
+		match := (!p.dirOnly || test.isDir) && p.re.MatchString(test.path)
+		if match != test.match {
+			t.Errorf("%q matching %q: expected %v, got %v", test.pattern, test.path, test.match, match)
+		}
+	}
+
+	for _, line := range []string{"", "# comment", "   "} {
+		if _, ok := parseIgnorePattern(line); ok {
+			t.Errorf("%q: expected no pattern", line)
+		}
+	}
+}
+
+func TestFindFilesWithIgnoreFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		".gitignore":            "*.log\n!keep.log\nbuild/\n",
+		".bindataignore":        "*.swp\n",
+		"index.html":            "",
+		"debug.log":             "",
+		"keep.log":              "",
+		"index.html.swp":        "",
+		"build/out.js":          "",
+		"sub/.gitignore":        "/local.txt\n",
+		"sub/local.txt":         "",
+		"sub/other/local.txt":   "",
+		"sub/.bindataignore":    "!*.swp\n",
+		"sub/notes.swp":         "",
+		"sub/nested/build/a.js": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rules, err := newIgnoreRules([]string{".gitignore", ".bindataignore"}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var toc []Asset
+	err = findFiles(dir, InputConfig{Path: dir, Prefix: dir, Recursive: true}, &toc, nil, []*regexp.Regexp{}, rules, make(map[string]int), make(map[string]bool))
+	if err != nil {
+		t.Fatalf("expected to be no error: %+v", err)
+	}
+
+	var names []string
+	for _, asset := range toc {
+		names = append(names, asset.Name)
+	}
+
+	expected := []string{"index.html", "keep.log", "sub/notes.swp", "sub/other/local.txt"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, names)
+			break
+		}
+	}
+}
+
+func TestNewIgnoreRulesLinkedWorkTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The work tree of a submodule, or a linked work tree, has a .git
+	// file instead of a directory. Rules above it do not apply.
+	files := map[string]string{
+		".gitignore":       "*.txt\n",
+		"wt/.git":          "gitdir: ../repo/.git/worktrees/wt\n",
+		"wt/.gitignore":    "*.log\n",
+		"wt/static/a.log":  "",
+		"wt/static/b.txt":  "",
+		"wt/static/c.html": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	static := filepath.Join(dir, "wt", "static")
+	rules, err := newIgnoreRules([]string{".gitignore"}, static)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, ignored := range map[string]bool{"a.log": true, "b.txt": false, "c.html": false} {
+		if got := rules.match(filepath.Join(static, name), false); got != ignored {
+			t.Errorf("%s: expected ignored %v, got %v", name, ignored, got)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// layersPath returns the path of the file holding the code of the layers,
+// written next to the given output file when there are layers.
+func layersPath(output string) string {
+	return strings.TrimSuffix(output, ".go") + "_layers.go"
+}
+
+// writePackFile writes the assets as an asset pack to c.PackFile. A pack
+// is an archive, whose ID serves as its checksum.
+func writePackFile(c *Config, toc []Asset) error {
+	p := newPacker(toc)
+	return writeFile(c.PackFile, func(w io.Writer) error {
+		return p.writeArchive(w, c, toc)
+	}, nil)
+}
+
+// writeLayers writes the code of the layers, which are sources of assets
+// consulted before the embedded assets: the overlay directory if c.Overlay
+// or c.OverlayEnv is set, and the asset packs loaded at runtime if
+// c.LoadPacks is set.
+func writeLayers(w io.Writer, c *Config) error {
+	err := writeHeader(w, c, nil, "")
+	if err != nil {
+		return err
+	}
+
+	imports := []string{"fmt", "io/ioutil", "os", "path/filepath", "sync/atomic"}
+	if c.LoadPacks {
+		imports = append(imports, "compress/gzip", "crypto/sha256", "encoding/binary", "io", "sync", "time")
+	}
+	if c.overlay() {
+		imports = append(imports, "path")
+	}
+
+	// The overlay takes precedence over the packs, so that a file patched
+	// on the host wins over everything else.
+	var layers []string
+	if c.overlay() {
+		layers = append(layers, `if dir, _ := _bindataOverlay.Load().(string); len(dir) != 0 {
+		layers = append(layers, bindataOverlay(dir))
+	}`)
+	}
+	if c.LoadPacks {
+		layers = append(layers, `layers = append(layers, bindataPackLayers()...)`)
+	}
+
+	// Debug code falls back to the assets found on disk.
+	lookup := `f, ok := _bindata[name]
+	return f, ok`
+	names := "for name := range _bindata"
+	if c.dynamic() {
+		lookup = "return bindataDisk(name)"
+		names = "for _, name := range bindataDiskNames()"
+	}
+
+	_, err = fmt.Fprintf(w, `import (
+	"%[1]s"
+)
+
+// bindataLayer is a source of assets, which is consulted before the
+// embedded assets.
+type bindataLayer interface {
+	// lookup returns the function loading the named asset, if the layer
+	// holds it.
+	lookup(name string) (func() (*asset, error), bool)
+
+	// names returns the names of the assets of the layer.
+	names() []string
+}
+
+// bindataLayers returns the current layers, the first taking precedence.
+func bindataLayers() []bindataLayer {
+	var layers []bindataLayer
+	%[2]s
+	return layers
+}
+
+// bindataLookup returns the function loading the named asset from the
+// first layer holding it, or from the embedded assets.
+func bindataLookup(name string) (func() (*asset, error), bool) {
+	for _, layer := range bindataLayers() {
+		if f, ok := layer.lookup(name); ok {
+			return f, true
+		}
+	}
+	%[3]s
+}
+
+// bindataNames returns the names of the assets of the layers and of the
+// embedded assets, once each.
+func bindataNames() []string {
+	seen := make(map[string]bool, len(_bindata))
+	names := make([]string, 0, len(_bindata))
+	for _, layer := range bindataLayers() {
+		for _, name := range layer.names() {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	%[4]s {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+`, strings.Join(imports, "\"\n\t\""), strings.Join(layers, "\n\t"), lookup, names)
+	if err != nil {
+		return err
+	}
+
+	if c.overlay() {
+		if err = writeOverlay(w, c); err != nil {
+			return err
+		}
+	}
+	if c.LoadPacks {
+		return writePackLayers(w)
+	}
+	return nil
+}
+
+// writeOverlay writes SetOverlay and the layer of the overlay directory,
+// which is initialized from the environment variable c.OverlayEnv if set.
+func writeOverlay(w io.Writer, c *Config) error {
+	env := ""
+	if len(c.OverlayEnv) > 0 {
+		env = fmt.Sprintf(`
+func init() {
+	if dir := os.Getenv(%q); len(dir) != 0 {
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		_bindataOverlay.Store(dir)
+	}
+}
+`, c.OverlayEnv)
+	}
+
+	_, err := fmt.Fprintf(w, `// _bindataOverlay holds the overlay directory, or an empty string.
+var _bindataOverlay atomic.Value
+%s
+// SetOverlay sets the overlay directory, whose files take precedence over
+// the embedded assets of the same name. Asset, AssetInfo, AssetNames,
+// AssetDir and AssetFile consult it first, and see the files added to or
+// removed from it at once. An empty dir disables the overlay.
+func SetOverlay(dir string) error {
+	if len(dir) != 0 {
+		fi, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("SetOverlay %%s: %%v", dir, err)
+		}
+		if !fi.IsDir() {
+			return fmt.Errorf("SetOverlay %%s: not a directory", dir)
+		}
+		if dir, err = filepath.Abs(dir); err != nil {
+			return fmt.Errorf("SetOverlay %%s: %%v", dir, err)
+		}
+	}
+	_bindataOverlay.Store(dir)
+	return nil
+}
+
+// bindataOverlay is the layer of the overlay directory.
+type bindataOverlay string
+
+func (dir bindataOverlay) lookup(name string) (func() (*asset, error), bool) {
+	// Names leaving the directory are never looked up on disk.
+	if len(name) == 0 || path.Clean("/"+name)[1:] != name {
+		return nil, false
+	}
+
+	filename := filepath.Join(string(dir), filepath.FromSlash(name))
+	fi, err := os.Stat(filename)
+	if err != nil || !fi.Mode().IsRegular() {
+		return nil, false
+	}
+	return func() (*asset, error) {
+		bytes, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("Read %%s at %%s: %%v", name, filename, err)
+		}
+		return &asset{bytes: bytes, info: fi}, nil
+	}, true
+}
+
+func (dir bindataOverlay) names() []string {
+	var names []string
+	dir.walk(string(dir), "", make(map[string]bool), &names)
+	return names
+}
+
+// walk adds the names of the regular files below filename to names,
+// following symbolic links like lookup does. A link leading back to a
+// directory being walked is skipped.
+func (dir bindataOverlay) walk(filename, name string, walking map[string]bool, names *[]string) {
+	real, err := filepath.EvalSymlinks(filename)
+	if err != nil || walking[real] {
+		return
+	}
+	walking[real] = true
+	defer delete(walking, real)
+
+	list, err := ioutil.ReadDir(filename)
+	if err != nil {
+		return
+	}
+	for _, fi := range list {
+		child := filepath.Join(filename, fi.Name())
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if fi, err = os.Stat(child); err != nil {
+				continue
+			}
+		}
+		switch {
+		case fi.IsDir():
+			dir.walk(child, path.Join(name, fi.Name()), walking, names)
+		case fi.Mode().IsRegular():
+			*names = append(*names, path.Join(name, fi.Name()))
+		}
+	}
+}
+
+`, env)
+	return err
+}
+
+// writePackLayers writes LoadPack and UnloadPack, and the layers of the
+// loaded asset packs.
+func writePackLayers(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `// bindataPack is an asset pack loaded by LoadPack. Its file is never
+// closed explicitly, as readers may still use a pack after it has been
+// replaced or unloaded. The file is closed when the garbage collector
+// collects it.
+type bindataPack struct {
+	path    string
+	file    *os.File
+	order   []string
+	entries map[string]bindataPackEntry
+}
+
+// bindataPackEntry locates the stored data of an asset in a pack, along
+// with its info.
+type bindataPackEntry struct {
+	offset  int64
+	length  int64
+	gzip    bool
+	mode    os.FileMode
+	modTime int64
+	link    string
+}
+
+var (
+	// _bindataPacks holds the loaded packs as a []*bindataPack, the last
+	// loaded first. It is replaced as a whole, so that readers always see
+	// a consistent set of packs.
+	_bindataPacks atomic.Value
+
+	// _bindataPacksMu serializes LoadPack and UnloadPack.
+	_bindataPacksMu sync.Mutex
+)
+
+// LoadPack loads the asset pack at path, as written by go-bindata -pack.
+// Its assets take precedence over the embedded assets and those of the
+// packs loaded before. Loading a pack again replaces it, e.g. after it
+// was updated. The pack is verified against its checksum, and the assets
+// of the new set of packs are visible at once.
+func LoadPack(path string) error {
+	p, err := bindataOpenPack(path)
+	if err != nil {
+		return fmt.Errorf("LoadPack %%s: %%v", path, err)
+	}
+
+	_bindataPacksMu.Lock()
+	defer _bindataPacksMu.Unlock()
+
+	old, _ := _bindataPacks.Load().([]*bindataPack)
+	packs := []*bindataPack{p}
+	for _, q := range old {
+		if q.path != p.path {
+			packs = append(packs, q)
+		}
+	}
+	_bindataPacks.Store(packs)
+	return nil
+}
+
+// UnloadPack unloads the asset pack loaded from path. It returns an error
+// if the pack is not loaded.
+func UnloadPack(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	_bindataPacksMu.Lock()
+	defer _bindataPacksMu.Unlock()
+
+	old, _ := _bindataPacks.Load().([]*bindataPack)
+	packs := make([]*bindataPack, 0, len(old))
+	for _, p := range old {
+		if p.path != abs {
+			packs = append(packs, p)
+		}
+	}
+	if len(packs) == len(old) {
+		return fmt.Errorf("UnloadPack %%s: not loaded", path)
+	}
+	_bindataPacks.Store(packs)
+	return nil
+}
+
+// bindataPackLayers returns the loaded packs, the last loaded first.
+func bindataPackLayers() []bindataLayer {
+	packs, _ := _bindataPacks.Load().([]*bindataPack)
+	layers := make([]bindataLayer, len(packs))
+	for i, p := range packs {
+		layers[i] = p
+	}
+	return layers
+}
+
+func (p *bindataPack) lookup(name string) (func() (*asset, error), bool) {
+	e, ok := p.entries[name]
+	if !ok {
+		return nil, false
+	}
+	return func() (*asset, error) { return p.load(name, e) }, true
+}
+
+func (p *bindataPack) names() []string {
+	return p.order
+}
+
+func (p *bindataPack) load(name string, e bindataPackEntry) (*asset, error) {
+	var r io.Reader = io.NewSectionReader(p.file, e.offset, e.length)
+	var gz *gzip.Reader
+	if e.gzip {
+		var err error
+		if gz, err = gzip.NewReader(r); err != nil {
+			return nil, fmt.Errorf("read %%q from %%s: %%v", name, p.path, err)
+		}
+		r = gz
+	}
+
+	data, err := ioutil.ReadAll(r)
+	var clErr error
+	if gz != nil {
+		clErr = gz.Close()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read %%q from %%s: %%v", name, p.path, err)
+	}
+	if clErr != nil {
+		return nil, fmt.Errorf("read %%q from %%s: %%v", name, p.path, clErr)
+	}
+
+	info := bindataFileInfo{name: name, size: int64(len(data)), mode: e.mode, modTime: time.Unix(e.modTime, 0)}
+	return &asset{bytes: data, info: info, link: e.link}, nil
+}
+
+// bindataOpenPack opens the pack at path, verifies its checksum and reads
+// its index.
+func bindataOpenPack(path string) (*bindataPack, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return nil, err
+	}
+	p, err := bindataReadPack(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	p.path = abs
+	return p, nil
+}
+
+func bindataReadPack(f *os.File) (*bindataPack, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	footer := make([]byte, %d)
+	if fi.Size() >= int64(len(footer)) {
+		_, err = f.ReadAt(footer, fi.Size()-int64(len(footer)))
+	}
+	if err != nil || fi.Size() < int64(len(footer)) || string(footer[32:]) != %q ||
+		int64(binary.LittleEndian.Uint64(footer[8:])) != fi.Size() {
+		return nil, fmt.Errorf("not an asset pack")
+	}
+
+	indexOffset := binary.LittleEndian.Uint64(footer)
+	if indexOffset > uint64(fi.Size())-uint64(len(footer)) {
+		return nil, fmt.Errorf("corrupt asset pack")
+	}
+
+	h := sha256.New()
+	if _, err = io.Copy(h, io.NewSectionReader(f, 0, fi.Size()-int64(len(footer)))); err != nil {
+		return nil, err
+	}
+	if string(h.Sum(nil)[:16]) != string(footer[16:32]) {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+
+	index := make([]byte, uint64(fi.Size())-uint64(len(footer))-indexOffset)
+	if _, err = f.ReadAt(index, int64(indexOffset)); err != nil {
+		return nil, err
+	}
+
+	corrupt := false
+	uvarint := func() uint64 {
+		x, k := binary.Uvarint(index)
+		if k <= 0 {
+			corrupt = true
+			return 0
+		}
+		index = index[k:]
+		return x
+	}
+	str := func() string {
+		n := uvarint()
+		if corrupt || n > uint64(len(index)) {
+			corrupt = true
+			return ""
+		}
+		s := string(index[:n])
+		index = index[n:]
+		return s
+	}
+
+	p := &bindataPack{file: f, entries: make(map[string]bindataPackEntry)}
+	for len(index) > 0 && !corrupt {
+		name := str()
+		offset, length := uvarint(), uvarint()
+		if corrupt || len(index) == 0 || offset > indexOffset || length > indexOffset-offset {
+			corrupt = true
+			break
+		}
+		e := bindataPackEntry{offset: int64(offset), length: int64(length), gzip: index[0]&1 != 0}
+		index = index[1:]
+		e.mode = os.FileMode(uvarint())
+		modTime, k := binary.Varint(index)
+		if k <= 0 {
+			corrupt = true
+			break
+		}
+		index = index[k:]
+		e.modTime = modTime
+		e.link = str()
+
+		if _, ok := p.entries[name]; !ok {
+			p.order = append(p.order, name)
+		}
+		p.entries[name] = e
+	}
+	if corrupt {
+		return nil, fmt.Errorf("corrupt asset pack")
+	}
+	return p, nil
+}
+
+`, archiveFooterSize, archiveMagic)
+	return err
+}
@@ -0,0 +1,209 @@
+package bindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const loadPacksMain = `package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+func show() {
+	names := AssetNames()
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := Asset(name)
+		fmt.Printf("%s=%q %v\n", name, data, err)
+	}
+	dir, err := AssetDir("")
+	sort.Strings(dir)
+	fmt.Println("dir", dir, err)
+}
+
+func main() {
+	if err := LoadPack(os.Args[1]); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	show()
+	if err := UnloadPack(os.Args[1]); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	show()
+	fmt.Println(UnloadPack(os.Args[1]))
+}
+`
+
+func TestLoadPacks(t *testing.T) {
+	dir, mod := newTestModule(t, loadPacksMain)
+	defer os.RemoveAll(dir)
+
+	base, over := filepath.Join(dir, "base"), filepath.Join(dir, "over")
+	writeTestFiles(t, map[string]string{
+		filepath.Join(base, "a.txt"): "base\n",
+		filepath.Join(base, "b.txt"): "base\n",
+		filepath.Join(over, "a.txt"): "pack\n",
+		filepath.Join(over, "c.txt"): "pack\n",
+	})
+
+	pc := NewConfig()
+	pc.Input = []InputConfig{{Path: over}}
+	pc.Prefix = over
+	pc.PackFile = filepath.Join(dir, "over.pack")
+	if err := Translate(pc); err != nil {
+		t.Fatal(err)
+	}
+
+	fd, err := os.Open(pc.PackFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, _ := fd.Stat()
+	a, err := readArchive(fd, fi.Size())
+	fd.Close()
+	if err != nil || a == nil || a.base != 0 || len(a.entries) != 2 {
+		t.Fatalf("unexpected pack %+v, %v", a, err)
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: base}}
+	c.Prefix = base
+	c.Output = filepath.Join(mod, "bindata.go")
+	c.LoadPacks = true
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	layers := filepath.Join(mod, "bindata_layers.go")
+	if _, err := os.Stat(layers); err != nil {
+		t.Fatal(err)
+	}
+
+	if !testing.Short() {
+		out, err := goCommand(t, mod, nil, "run", ".", pc.PackFile).CombinedOutput()
+		if err != nil {
+			t.Fatalf("run: %v\n%s", err, out)
+		}
+		expected := `a.txt="pack\n" <nil>
+b.txt="base\n" <nil>
+c.txt="pack\n" <nil>
+dir [a.txt b.txt c.txt] <nil>
+a.txt="base\n" <nil>
+b.txt="base\n" <nil>
+dir [a.txt b.txt] <nil>
+UnloadPack ` + pc.PackFile + `: not loaded
+`
+		if string(out) != expected {
+			t.Errorf("expected output\n%s\ngot\n%s", expected, out)
+		}
+
+		// A damaged pack is rejected.
+		data, err := ioutil.ReadFile(pc.PackFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data[0] ^= 0xff
+		writeTestFiles(t, map[string]string{pc.PackFile: string(data)})
+		if out, err := goCommand(t, mod, nil, "run", ".", pc.PackFile).CombinedOutput(); err == nil || !strings.Contains(string(out), "checksum mismatch") {
+			t.Errorf("expected checksum mismatch, got %v\n%s", err, out)
+		}
+	}
+
+	c.LoadPacks = false
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(layers); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", layers, err)
+	}
+}
+
+const overlayMain = `package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+func show() {
+	names := AssetNames()
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := Asset(name)
+		fmt.Printf("%s=%q %v\n", name, data, err)
+	}
+	dir, err := AssetDir("")
+	sort.Strings(dir)
+	fmt.Println("dir", dir, err)
+}
+
+func main() {
+	show()
+	_, err := Asset("../over/a.txt")
+	fmt.Println(err)
+	fmt.Println(SetOverlay(""))
+	show()
+}
+`
+
+func TestOverlay(t *testing.T) {
+	dir, mod := newTestModule(t, overlayMain)
+	defer os.RemoveAll(dir)
+
+	base := filepath.Join(dir, "base")
+	writeTestFiles(t, map[string]string{
+		filepath.Join(base, "a.txt"):               "base\n",
+		filepath.Join(base, "b.txt"):               "base\n",
+		filepath.Join(dir, "over", "a.txt"):        "over\n",
+		filepath.Join(dir, "over", "new", "c.txt"): "over\n",
+	})
+
+	// Links are followed when listing the overlay as when looking it up,
+	// except those leading back to a directory being listed.
+	links := map[string]string{"link.txt": "new/c.txt", "linked": "new", "loop": "."}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, "over", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, debug := range []bool{false, true} {
+		c := NewConfig()
+		c.Input = []InputConfig{{Path: base}}
+		c.Prefix = base
+		c.Output = filepath.Join(mod, "bindata.go")
+		c.Debug = debug
+		c.OverlayEnv = "BINDATA_TEST_OVERLAY"
+		if err := Translate(c); err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := goCommand(t, mod, []string{"BINDATA_TEST_OVERLAY=../over"}, "run", ".").CombinedOutput()
+		if err != nil {
+			t.Fatalf("run: %v\n%s", err, out)
+		}
+		expected := `a.txt="over\n" <nil>
+b.txt="base\n" <nil>
+link.txt="over\n" <nil>
+linked/c.txt="over\n" <nil>
+new/c.txt="over\n" <nil>
+dir [a.txt b.txt link.txt linked new] <nil>
+Asset ../over/a.txt not found
+<nil>
+a.txt="base\n" <nil>
+b.txt="base\n" <nil>
+dir [a.txt b.txt] <nil>
+`
+		if string(out) != expected {
+			t.Errorf("debug %v: expected output\n%s\ngot\n%s", debug, expected, out)
+		}
+	}
+}
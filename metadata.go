@@ -0,0 +1,341 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ModTimeSource defines where a metadata rule takes the modification
+// time of an asset from.
+type ModTimeSource int
+
+const (
+	// ModTimeFile keeps the modification time of the file, unless the
+	// rule sets a fixed time.
+	ModTimeFile ModTimeSource = iota
+
+	// ModTimeGit uses the time of the last git commit touching the file,
+	// following the first parent of merges, which count as touching the
+	// files they change. Files which git does not track, including those
+	// outside of a git work tree, keep the time an earlier rule or the
+	// global ModTime set, or else their own modification time.
+	ModTimeGit
+
+	// ModTimeSourceDateEpoch uses the timestamp in the SOURCE_DATE_EPOCH
+	// environment variable, as defined by reproducible-builds.org.
+	ModTimeSourceDateEpoch
+)
+
+// modeMask holds the mode bits a metadata rule may set.
+const modeMask = os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+
+// MetadataRule overrides the mode and modification time of the assets
+// whose name matches Pattern. Rules are applied in order after the global
+// Mode and ModTime overrides, so later rules win over earlier ones.
+type MetadataRule struct {
+	// Pattern is a glob matched against asset names, with the syntax of
+	// gitignore(5): `*` does not match a slash, `**` matches across
+	// directories and a pattern without a slash matches at any depth.
+	Pattern string
+
+	// When nonzero, use this as mode. Besides the permission bits it may
+	// hold os.ModeSetuid, os.ModeSetgid and os.ModeSticky.
+	Mode os.FileMode
+
+	// When nonzero, use this as unix timestamp.
+	ModTime int64
+
+	// ModTimeSource selects a modification time other than the file's.
+	ModTimeSource ModTimeSource
+
+	re *regexp.Regexp
+}
+
+// ParseMetadataRule parses a rule in the form `pattern:key=value,...`,
+// where the keys are `mode`, an octal mode such as 0755 or 04755, and
+// `mtime`, a unix timestamp, `git` or `epoch` for SOURCE_DATE_EPOCH.
+//
+//	ex:
+//	    bin/*.sh:mode=0755
+//	    **:mode=0644,mtime=git
+func ParseMetadataRule(rule string) (MetadataRule, error) {
+	var r MetadataRule
+
+	i := strings.LastIndex(rule, ":")
+	if i < 0 {
+		return r, fmt.Errorf("invalid metadata rule %q: expected pattern:key=value", rule)
+	}
+	r.Pattern = rule[:i]
+
+	for _, attr := range strings.Split(rule[i+1:], ",") {
+		kv := strings.SplitN(attr, "=", 2)
+		if len(kv) != 2 {
+			return r, fmt.Errorf("invalid metadata rule %q: expected key=value, got %q", rule, attr)
+		}
+
+		switch key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]); key {
+		case "mode":
+			mode, err := strconv.ParseUint(value, 8, 32)
+			if err != nil || mode == 0 || mode > 07777 {
+				return r, fmt.Errorf("invalid metadata rule %q: bad mode %q", rule, value)
+			}
+			r.Mode = os.FileMode(mode) & os.ModePerm
+			if mode&04000 != 0 {
+				r.Mode |= os.ModeSetuid
+			}
+			if mode&02000 != 0 {
+				r.Mode |= os.ModeSetgid
+			}
+			if mode&01000 != 0 {
+				r.Mode |= os.ModeSticky
+			}
+		case "mtime":
+			switch value {
+			case "git":
+				r.ModTimeSource = ModTimeGit
+			case "epoch":
+				r.ModTimeSource = ModTimeSourceDateEpoch
+			default:
+				modTime, err := strconv.ParseInt(value, 10, 64)
+				if err != nil || modTime == 0 {
+					return r, fmt.Errorf("invalid metadata rule %q: bad mtime %q", rule, value)
+				}
+				r.ModTime = modTime
+			}
+		default:
+			return r, fmt.Errorf("invalid metadata rule %q: unknown key %q", rule, key)
+		}
+	}
+
+	return r, r.compile()
+}
+
+// compile prepares the pattern of the rule for matching.
+func (r *MetadataRule) compile() error {
+	if r.re != nil {
+		return nil
+	}
+
+	re, err := compileGlob(r.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid metadata rule pattern %q: %v", r.Pattern, err)
+	}
+
+	r.re = re
+	return nil
+}
+
+// metadata returns the size, mode and modification time recorded for the
+// asset, with the overrides of the configuration applied. The os.ModeDir
+// and os.ModeSymlink bits of directories and preserved links are kept.
+func (asset *Asset) metadata(c *Config) (size int64, mode uint, modTime int64, err error) {
+	fi, err := os.Lstat(asset.Path)
+	if err != nil {
+		return
+	}
+	if len(asset.Link) == 0 && fi.Mode()&os.ModeSymlink != 0 {
+		// Followed links report the file they point to.
+		if fi, err = os.Stat(asset.Path); err != nil {
+			return
+		}
+	}
+
+	typ := uint(fi.Mode() & (os.ModeDir | os.ModeSymlink))
+	mode = uint(fi.Mode())
+	modTime = fi.ModTime().Unix()
+	size = fi.Size()
+	if fi.IsDir() {
+		size = 0
+	}
+	if len(asset.Link) > 0 {
+		// Links serve the contents of the file they point to, if any,
+		// as openAsset does.
+		size = 0
+		if target, serr := os.Stat(asset.Path); serr == nil && target.Mode().IsRegular() {
+			size = target.Size()
+		}
+	}
+	if c.NoMetadata {
+		mode = 0
+		modTime = 0
+		size = 0
+	}
+
+	overMode, overModTime, err := asset.overrides(c, fi.IsDir())
+	if err != nil {
+		return
+	}
+	if overMode > 0 {
+		mode = overMode
+	}
+	if overModTime > 0 {
+		modTime = overModTime
+	}
+	mode |= typ
+	return
+}
+
+// overrides returns the mode and modification time which the global
+// overrides and the metadata rules set for the asset. Zero values keep
+// the metadata of the file.
+func (asset *Asset) overrides(c *Config, isDir bool) (mode uint, modTime int64, err error) {
+	if c.Mode > 0 {
+		mode = uint(os.ModePerm) & c.Mode
+	}
+	if c.ModTime > 0 {
+		modTime = c.ModTime
+	}
+
+	for i := range c.MetadataRules {
+		rule := &c.MetadataRules[i]
+		if err = rule.compile(); err != nil {
+			return
+		}
+		if !rule.re.MatchString(asset.Name) {
+			continue
+		}
+
+		if rule.Mode != 0 {
+			mode = uint(rule.Mode & modeMask)
+		}
+		if rule.ModTime != 0 {
+			modTime = rule.ModTime
+		}
+
+		switch rule.ModTimeSource {
+		case ModTimeGit:
+			var t int64
+			if t, err = c.gitModTime(asset.Path); err != nil {
+				return
+			}
+			if t > 0 {
+				modTime = t
+			}
+		case ModTimeSourceDateEpoch:
+			if modTime, err = sourceDateEpoch(); err != nil {
+				return
+			}
+		}
+	}
+
+	if isDir && mode > 0 {
+		// Like chmod a+X, directories are searchable where readable.
+		mode |= (mode & 0444) >> 2
+	}
+	return
+}
+
+// gitModTime returns the commit time of the last commit touching the given
+// path, or zero if git does not track it. The commit times of all files of
+// the inputs in a work tree are read with a single git log, and kept for
+// the other assets.
+func (c *Config) gitModTime(path string) (int64, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return 0, err
+	}
+
+	root := filepath.Dir(abs)
+	for !isWorkTreeRoot(root) {
+		if root == filepath.Dir(root) {
+			return 0, nil
+		}
+		root = filepath.Dir(root)
+	}
+
+	times, ok := c.gitTimes[root]
+	if !ok {
+		if times, err = readGitTimes(root, c.gitPathspecs(root)); err != nil {
+			return 0, err
+		}
+		if c.gitTimes == nil {
+			c.gitTimes = make(map[string]map[string]int64)
+		}
+		c.gitTimes[root] = times
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return 0, err
+	}
+	return times[filepath.ToSlash(rel)], nil
+}
+
+// gitPathspecs returns the paths of the inputs inside the work tree at
+// root, relative to it, or the whole work tree if there are none.
+func (c *Config) gitPathspecs(root string) []string {
+	var specs []string
+	for _, input := range c.Input {
+		abs, err := filepath.Abs(input.Path)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		specs = append(specs, filepath.ToSlash(rel))
+	}
+	if len(specs) == 0 {
+		specs = []string{"."}
+	}
+	return specs
+}
+
+// readGitTimes reads the commit time of the last commit touching each file
+// below the given paths of the work tree at root, by the slash separated
+// path of the file relative to root. Merges are compared with their first
+// parent, whose history is followed, so a file changed by a merge has the
+// time the merge was committed.
+func readGitTimes(root string, pathspecs []string) (map[string]int64, error) {
+	args := append([]string{"log", "-z", "-m", "--first-parent", "--format=%x01%ct", "--name-only", "--"}, pathspecs...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = root
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git commit times in %s: %v", root, err)
+	}
+
+	// Commits are listed newest first, each as its time followed by the
+	// names of the files it touched.
+	times := make(map[string]int64)
+	var t int64
+	for _, field := range strings.Split(string(out), "\x00") {
+		if strings.HasPrefix(field, "\x01") {
+			if t, err = strconv.ParseInt(field[1:], 10, 64); err != nil {
+				return nil, fmt.Errorf("git commit times in %s: %v", root, err)
+			}
+			continue
+		}
+		name := strings.TrimPrefix(field, "\n")
+		if _, ok := times[name]; !ok && len(name) > 0 {
+			times[name] = t
+		}
+	}
+	return times, nil
+}
+
+// sourceDateEpoch returns the timestamp set in SOURCE_DATE_EPOCH.
+func sourceDateEpoch() (int64, error) {
+	value := os.Getenv("SOURCE_DATE_EPOCH")
+	if len(value) == 0 {
+		return 0, fmt.Errorf("SOURCE_DATE_EPOCH is not set")
+	}
+
+	epoch, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %v", value, err)
+	}
+
+	return epoch, nil
+}
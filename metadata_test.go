@@ -0,0 +1,142 @@
+package bindata
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseMetadataRule(t *testing.T) {
+	rule, err := ParseMetadataRule("bin/*.sh:mode=04755,mtime=1500000000")
+	if err != nil {
+		t.Fatalf("expected to be no error: %+v", err)
+	}
+	if rule.Pattern != "bin/*.sh" || rule.Mode != os.ModeSetuid|0755 || rule.ModTime != 1500000000 {
+		t.Errorf("Unexpected rule %+v", rule)
+	}
+
+	rule, err = ParseMetadataRule("**:mtime=git")
+	if err != nil || rule.ModTimeSource != ModTimeGit {
+		t.Errorf("Unexpected rule %+v: %v", rule, err)
+	}
+
+	for _, invalid := range []string{"*.sh", "*.sh:mode=999", "*.sh:owner=root", "*.sh:mtime=yesterday"} {
+		if _, err := ParseMetadataRule(invalid); err == nil {
+			t.Errorf("%q: expected an error", invalid)
+		}
+	}
+}
+
+func TestMetadataRules(t *testing.T) {
+	c := NewConfig()
+	c.Mode = 0644
+	for _, r := range []string{"**:mtime=1500000000", "*.asset:mode=0600", "b/*:mode=0755,mtime=1600000000"} {
+		rule, err := ParseMetadataRule(r)
+		if err != nil {
+			t.Fatalf("expected to be no error: %+v", err)
+		}
+		c.MetadataRules = append(c.MetadataRules, rule)
+	}
+
+	tests := []struct {
+		name    string
+		mode    os.FileMode
+		modTime int64
+	}{
+		{"a/test.asset", 0600, 1500000000},
+		{"b/test.asset", 0755, 1600000000},
+		{"test.other", 0644, 1500000000},
+	}
+
+	for _, test := range tests {
+		asset := Asset{Path: "testdata/in/test.asset", Name: test.name}
+		_, mode, modTime, err := asset.metadata(c)
+		if err != nil {
+			t.Fatalf("expected to be no error: %+v", err)
+		}
+		if os.FileMode(mode) != test.mode || modTime != test.modTime {
+			t.Errorf("%s: expected mode %v and modtime %d, got %v and %d", test.name, test.mode, test.modTime, os.FileMode(mode), modTime)
+		}
+	}
+}
+
+func TestGitModTime(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	repo, other := filepath.Join(dir, "repo"), filepath.Join(dir, "other")
+	for _, name := range []string{"repo/in/committed", "repo/in/merged", "repo/in/untracked", "other/file"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, time.Unix(1400000000, 0), time.Unix(1400000000, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The merged file is only added by a merge.
+	for _, step := range []struct {
+		date string
+		args []string
+	}{
+		{"1700000000", []string{"init", "-q"}},
+		{"1700000000", []string{"add", "in/committed"}},
+		{"1700000000", []string{"commit", "-q", "-m", "x"}},
+		{"1800000000", []string{"checkout", "-q", "-b", "side"}},
+		{"1800000000", []string{"commit", "-q", "--allow-empty", "-m", "side"}},
+		{"1800000000", []string{"checkout", "-q", "-"}},
+		{"1800000000", []string{"merge", "-q", "--no-ff", "--no-commit", "side"}},
+		{"1800000000", []string{"add", "in/merged"}},
+		{"1800000000", []string{"commit", "-q", "-m", "merge"}},
+	} {
+		args := append([]string{"-c", "user.name=x", "-c", "user.email=x@x"}, step.args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+step.date+" +0000", "GIT_AUTHOR_DATE="+step.date+" +0000")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", step.args, err, out)
+		}
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: filepath.Join(repo, "in")}, {Path: other}}
+	for _, r := range []string{"untracked:mtime=1500000000", "**:mtime=git"} {
+		rule, err := ParseMetadataRule(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.MetadataRules = append(c.MetadataRules, rule)
+	}
+
+	// Files git does not track keep the time of an earlier rule, or else
+	// their own modification time.
+	for path, expected := range map[string]int64{
+		filepath.Join(repo, "in", "committed"): 1700000000,
+		filepath.Join(repo, "in", "merged"):    1800000000,
+		filepath.Join(repo, "in", "untracked"): 1500000000,
+		filepath.Join(other, "file"):           1400000000,
+	} {
+		asset := Asset{Path: path, Name: filepath.Base(path)}
+		_, _, modTime, err := asset.metadata(c)
+		if err != nil || modTime != expected {
+			t.Errorf("%s: expected modtime %d, got %d, %v", path, expected, modTime, err)
+		}
+	}
+	if len(c.gitTimes) != 1 {
+		t.Errorf("expected the commit times of one work tree to be kept, got %v", c.gitTimes)
+	}
+}
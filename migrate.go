@@ -0,0 +1,175 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// compatPath returns the path of the file holding the functions kept from
+// the code migrated to the given output file.
+func compatPath(output string) string {
+	return strings.TrimSuffix(output, ".go") + "_compat.go"
+}
+
+// Migrate replaces code generated by go-bindata, possibly by an earlier
+// version, with the output of Embed mode. The assets are read from the
+// file at path and restored below dir, which must be empty or missing,
+// with their recorded modes and modification times. The package name, the
+// build tags and the presence of AssetFile are taken over from the file,
+// the other options from c. The output defaults to the file itself.
+//
+// Exported functions of the file serving a single asset, as generated by
+// earlier versions, are kept in a file named after the output file with
+// a _compat suffix. Other exported functions which the new output does
+// not declare are reported to c.Log.
+func Migrate(path, dir string, c *Config) error {
+	g, err := ReadGenerated(path)
+	if err != nil {
+		return err
+	}
+
+	if list, err := ioutil.ReadDir(dir); err == nil && len(list) > 0 {
+		return fmt.Errorf("cannot restore assets to %s: directory is not empty", dir)
+	}
+
+	absolute, metadata := 0, false
+	for _, asset := range g.Assets {
+		if strings.HasPrefix(asset.Name, "/") {
+			absolute++
+		}
+		if asset.Mode != 0 {
+			metadata = true
+		}
+	}
+	if absolute > 0 && absolute < len(g.Assets) {
+		return fmt.Errorf("cannot migrate a mix of absolute and relative asset names")
+	}
+
+	if err = g.Restore(dir); err != nil {
+		return err
+	}
+
+	input := InputConfig{Path: dir, Prefix: dir, Recursive: true, Symlinks: SymlinkPreserve}
+	if absolute > 0 {
+		input.Rename = []RenameRule{{Pattern: regexp.MustCompile("^"), Replace: "/"}}
+	}
+
+	c.Input = []InputConfig{input}
+	c.Package = g.Package
+	c.Tags = g.Tags
+	c.HttpFileSystem = g.declares("AssetFile")
+	c.NoMetadata = len(g.Assets) > 0 && !metadata
+	c.Embed = true
+	c.AssetMeta = false
+	c.Ignore = nil
+	c.IgnoreFiles = nil
+	if len(c.Output) == 0 {
+		c.Output = path
+	}
+
+	if err = Translate(c); err != nil {
+		return err
+	}
+
+	out, err := ReadGenerated(c.Output)
+	if err != nil {
+		return err
+	}
+
+	return writeCompat(c, g, out)
+}
+
+// writeCompat writes the functions of the migrated code g serving a single
+// asset, which the new output out does not declare, and reports the other
+// exported functions it lacks.
+func writeCompat(c *Config, g, out *Generated) error {
+	funcs := make(map[string]string)
+	for _, asset := range g.Assets {
+		funcs[asset.Func] = asset.Name
+	}
+
+	var compat []string
+	for _, name := range g.Funcs {
+		if out.declares(name) {
+			continue
+		}
+
+		body := ""
+		if asset, ok := funcs[name]; ok {
+			body = compatBody(g.decls[name], asset)
+		}
+		if len(body) == 0 {
+			c.logf("bindata: %s is not declared by the migrated code", name)
+			continue
+		}
+		compat = append(compat, body)
+	}
+
+	path := compatPath(c.Output)
+	if len(compat) == 0 {
+		if isGenerated(path) {
+			return os.Remove(path)
+		}
+		return nil
+	}
+
+	sort.Strings(compat)
+	return writeGoFile(path, func(w io.Writer) error {
+		err := writeHeader(w, c, nil, "")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprint(w, strings.Join(compat, "\n"))
+		return err
+	})
+}
+
+// compatBody returns the declaration of a function serving the asset
+// through the API of the new output, with the signature of fn, or an
+// empty string if the signature is not supported.
+func compatBody(fn *ast.FuncDecl, asset string) string {
+	if fn.Type.Params.NumFields() > 0 || fn.Type.Results == nil {
+		return ""
+	}
+
+	var results []string
+	for _, field := range fn.Type.Results.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			results = append(results, types.ExprString(field.Type))
+		}
+	}
+
+	var signature, call string
+	switch strings.Join(results, ", ") {
+	case "*asset, error":
+		signature, call = "(*asset, error)", fmt.Sprintf("_bindata[%q]()", asset)
+	case "[]byte, error":
+		signature, call = "([]byte, error)", fmt.Sprintf("Asset(%q)", asset)
+	case "[]byte":
+		signature, call = "[]byte", fmt.Sprintf("MustAsset(%q)", asset)
+	default:
+		return ""
+	}
+
+	return fmt.Sprintf(`// %s returns the asset %q, as in the migrated code.
+func %s() %s {
+	return %s
+}
+`, fn.Name.Name, asset, fn.Name.Name, signature, call)
+}
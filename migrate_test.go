@@ -0,0 +1,138 @@
+package bindata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// oldBindata is code in the style of early go-bindata releases, with
+// exported functions per asset and no recorded info.
+const oldBindata = `// +build assets
+
+package static
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+func bindata_read(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("Read %%q: %%v", name, err)
+	}
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, gz)
+	gz.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("Read %%q: %%v", name, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var _index_html = %#v
+
+func Index_html() ([]byte, error) {
+	return bindata_read(_index_html, "index.html")
+}
+
+func css_app_css() ([]byte, error) {
+	return []byte("body {}"), nil
+}
+
+var _css_app_css = []byte("body {}")
+
+func css_app_css_bytes() ([]byte, error) {
+	return _css_app_css, nil
+}
+
+func Asset(name string) ([]byte, error) {
+	if f, ok := _bindata[name]; ok {
+		return f()
+	}
+	return nil, fmt.Errorf("Asset %%s not found", name)
+}
+
+func AssetString(name string) (string, error) {
+	data, err := Asset(name)
+	return string(data), err
+}
+
+var _bindata = map[string]func() ([]byte, error){
+	"index.html":  Index_html,
+	"css/app.css": css_app_css_bytes,
+}
+`
+
+func TestMigrate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("<html></html>"))
+	gz.Close()
+
+	path := filepath.Join(dir, "bindata.go")
+	if err := ioutil.WriteFile(path, []byte(fmt.Sprintf(oldBindata, buf.Bytes())), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var log bytes.Buffer
+	c := NewConfig()
+	c.Output = ""
+	c.Log = &log
+	c.NoLegacy = true
+	assets := filepath.Join(dir, "assets")
+	if err := Migrate(path, assets, c); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range map[string]string{"index.html": "<html></html>", "css/app.css": "body {}"} {
+		data, err := ioutil.ReadFile(filepath.Join(assets, name))
+		if err != nil || string(data) != content {
+			t.Errorf("%s: expected %q, got %q, %v", name, content, data, err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"// +build assets,go1.16\n", "package static\n", "//go:embed assets/css/app.css\n"} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("expected output to contain %q", s)
+		}
+	}
+
+	data, err = ioutil.ReadFile(compatPath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "func Index_html() ([]byte, error) {\n\treturn Asset(\"index.html\")\n}\n"
+	if !strings.Contains(string(data), expected) || !strings.Contains(string(data), "// +build assets\n") {
+		t.Errorf("expected compat file to contain %q, got:\n%s", expected, data)
+	}
+
+	if !strings.Contains(log.String(), "AssetString is not declared") {
+		t.Errorf("expected AssetString to be reported, got %q", log.String())
+	}
+
+	if err := Migrate(path, assets, c); err == nil {
+		t.Errorf("expected error restoring to a directory which is not empty")
+	}
+}
@@ -0,0 +1,80 @@
+package bindata
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// printAssetsMain prints the contents of all assets of the generated code.
+const printAssetsMain = `package main
+
+import "fmt"
+
+func main() {
+	for _, name := range AssetNames() {
+		data, err := Asset(name)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%s=%q\n", name, data)
+	}
+}
+`
+
+// newTestModule creates a temporary directory holding a Go module with the
+// given main.go in its subdirectory mod, to which tests write generated
+// code. The caller removes dir.
+func newTestModule(t *testing.T, main string) (dir, mod string) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod = filepath.Join(dir, "mod")
+	writeTestFiles(t, map[string]string{
+		filepath.Join(mod, "go.mod"):  "module x\n\ngo 1.12\n",
+		filepath.Join(mod, "main.go"): main,
+	})
+	return dir, mod
+}
+
+// writeTestFiles writes the files, by path, creating their directories.
+func writeTestFiles(t *testing.T, files map[string]string) {
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// goCommand returns the command running the go tool with args in the
+// module mod, with env added to the environment. The test is skipped in
+// short mode, or when there is no go tool.
+func goCommand(t *testing.T, mod string, env []string, args ...string) *exec.Cmd {
+	if testing.Short() {
+		t.Skip("skipping build of generated code in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	cmd := exec.Command(goTool, args...)
+	cmd.Dir = mod
+	cmd.Env = append(os.Environ(), env...)
+	return cmd
+}
+
+// buildTestModule builds the module mod into the stripped executable exe.
+func buildTestModule(t *testing.T, mod, exe string, env ...string) {
+	out, err := goCommand(t, mod, env, "build", "-ldflags=-s -w", "-o", exe, ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("build: %v\n%s", err, out)
+	}
+}
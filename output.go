@@ -0,0 +1,503 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// writeGoFile writes the Go source produced by write to path. The source is
+// first written to a temporary file in the same directory, parsed and
+// formatted, and only then renamed into place. On error the previous
+// contents of path are left untouched.
+func writeGoFile(path string, write func(w io.Writer) error) error {
+	return writeFile(path, write, formatGoFile)
+}
+
+// writeDataFile writes the Go source holding embedded data produced by
+// write to path, like writeGoFile. The source is parsed and formatted one
+// top-level declaration at a time, so that the memory taken by the syntax
+// trees grows with the largest asset rather than with the file.
+func writeDataFile(path string, write func(w io.Writer) error) error {
+	return writeFile(path, write, formatDataFile)
+}
+
+// writeFile writes the contents produced by write to path through a
+// temporary file in the same directory, which is renamed into place once
+// it is complete. If check is not nil, it is applied to the temporary file
+// before. On error the previous contents of path are left untouched.
+func writeFile(path string, write func(w io.Writer) error, check func(path string, tmp *os.File) error) (err error) {
+	dir, name := filepath.Split(path)
+	if dir != "" {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create output directory: %v", err)
+		}
+	} else {
+		// TempFile would use the default temporary directory, which
+		// may be on another file system than the output.
+		dir = "."
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+name+".tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		tmp.Close()
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	// Create a buffered writer for better performance.
+	bfd := bufio.NewWriter(tmp)
+	if err = write(bfd); err != nil {
+		return err
+	}
+	if err = bfd.Flush(); err != nil {
+		return err
+	}
+
+	if check != nil {
+		if err = check(path, tmp); err != nil {
+			return err
+		}
+	}
+
+	// Keep the mode of a previous output, the temporary file is private.
+	mode := os.FileMode(0644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	if err = tmp.Chmod(mode); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// formatGoFile makes sure the generated code in tmp compiles into a syntax
+// tree, and stores it the way gofmt would have.
+func formatGoFile(path string, tmp *os.File) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, tmp.Name(), nil, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("generated code for %s is invalid: %v", path, err)
+	}
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err = tmp.Truncate(0); err != nil {
+		return err
+	}
+
+	bfd := bufio.NewWriter(tmp)
+	if err = format.Node(bfd, fset, file); err != nil {
+		return fmt.Errorf("format generated code for %s: %v", path, err)
+	}
+	return bfd.Flush()
+}
+
+// formatDataFile makes sure each top-level declaration of the generated
+// code in tmp compiles into a syntax tree, and stores the code the way
+// gofmt would have. Declarations of data literals are kept as generated,
+// as formatting long concatenations takes time growing with the square
+// of their length; they are generated in the layout gofmt gives them.
+func formatDataFile(path string, tmp *os.File) error {
+	out, err := ioutil.TempFile(filepath.Dir(tmp.Name()), filepath.Base(tmp.Name()))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		out.Close()
+		os.Remove(out.Name())
+	}()
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	bfd := bufio.NewWriter(out)
+	line, header := 1, true
+	err = splitGoSource(bufio.NewReader(tmp), func(chunk []byte, cuts []int) error {
+		start := line
+		line += bytes.Count(chunk, newline)
+		if len(bytes.TrimSpace(chunk)) == 0 {
+			return nil
+		}
+		if !header {
+			if _, err := bfd.Write(newline); err != nil {
+				return err
+			}
+		}
+		err := formatChunk(bfd, tmp.Name(), start, chunk, cuts, header)
+		header = false
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("generated code for %s is invalid: %v", path, err)
+	}
+	if err = bfd.Flush(); err != nil {
+		return err
+	}
+
+	// Copy the formatted code back, tmp is the file renamed into place.
+	if _, err = out.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err = tmp.Truncate(0); err != nil {
+		return err
+	}
+	bfd = bufio.NewWriter(tmp)
+	if _, err = io.Copy(bfd, out); err != nil {
+		return err
+	}
+	return bfd.Flush()
+}
+
+// chunkPackage is the package clause a chunk of source following the
+// header is parsed with.
+const chunkPackage = "package p\n"
+
+// pieceSize is the size of source past which the rows of a byte slice
+// literal are parsed a piece at a time. The syntax tree of such a literal
+// takes many times the size of its source.
+const pieceSize = 1 << 20
+
+// pieceOpening is the source the rows of a byte slice literal following a
+// cut are parsed after.
+const pieceOpening = chunkPackage + "var _ = []byte{\n"
+
+// formatChunk parses a chunk of source starting at the given line of
+// filename, and writes it formatted to w. The header holds the package
+// clause, other chunks hold top-level declarations. A chunk declaring
+// data literals may be cut into pieces between rows of a byte slice
+// literal, which are parsed one at a time.
+func formatChunk(w io.Writer, filename string, line int, chunk []byte, cuts []int, header bool) error {
+	if !header && len(cuts) > 0 {
+		data, err := parsePieces(filename, line, chunk, cuts)
+		if err != nil {
+			return err
+		}
+		if data {
+			_, err = w.Write(append(bytes.TrimRight(chunk, "\n"), '\n'))
+			return err
+		}
+	}
+
+	src := chunk
+	prefix := ""
+	if !header {
+		prefix = chunkPackage
+		src = append([]byte(prefix), chunk...)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parseChunk(fset, filename, line, prefix, src)
+	if err != nil {
+		return err
+	}
+
+	if !header && isDataDecl(file) {
+		_, err = w.Write(append(bytes.TrimRight(chunk, "\n"), '\n'))
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err = format.Node(&buf, fset, file); err != nil {
+		return err
+	}
+	formatted := buf.Bytes()
+	if !header {
+		formatted = bytes.TrimLeft(bytes.TrimPrefix(formatted, []byte(chunkPackage)), "\n")
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// parsePieces parses a chunk of source starting at the given line of
+// filename in the pieces it is cut into, and reports whether it declares
+// nothing but data literals. The cuts are between rows of a byte slice
+// literal, which is closed at the end of a piece and opened again at the
+// start of the next.
+func parsePieces(filename string, line int, chunk []byte, cuts []int) (bool, error) {
+	start := 0
+	for i := 0; i <= len(cuts); i++ {
+		end := len(chunk)
+		if i < len(cuts) {
+			end = cuts[i]
+		}
+
+		prefix := chunkPackage
+		if i > 0 {
+			prefix = pieceOpening
+		}
+		src := append([]byte(prefix), chunk[start:end]...)
+		if i < len(cuts) {
+			src = append(src, "}\n"...)
+		}
+
+		file, err := parseChunk(token.NewFileSet(), filename, line, prefix, src)
+		if err != nil {
+			return false, err
+		}
+		if !isDataDecl(file) {
+			return false, nil
+		}
+
+		line += bytes.Count(chunk[start:end], newline)
+		start = end
+	}
+	return true, nil
+}
+
+// parseChunk parses src, a chunk of source starting at the given line of
+// filename after prefix. The positions of errors are those in filename.
+func parseChunk(fset *token.FileSet, filename string, line int, prefix string, src []byte) (*ast.File, error) {
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if list, ok := err.(scanner.ErrorList); ok {
+		offset := line - 1 - strings.Count(prefix, "\n")
+		for _, e := range list {
+			e.Pos.Line += offset
+		}
+	}
+	return file, err
+}
+
+// isDataDecl reports whether the file declares nothing but variables
+// holding data literals.
+func isDataDecl(file *ast.File) bool {
+	if len(file.Decls) != 1 {
+		return false
+	}
+	decl, ok := file.Decls[0].(*ast.GenDecl)
+	if !ok || decl.Tok != token.VAR {
+		return false
+	}
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ValueSpec)
+		if spec.Type != nil || len(spec.Values) == 0 {
+			return false
+		}
+		for _, value := range spec.Values {
+			if !isDataLiteral(value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isDataLiteral reports whether expr is a string literal, a concatenation
+// of them, or a byte slice made of them or of integer literals.
+func isDataLiteral(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		return expr.Kind == token.STRING
+	case *ast.BinaryExpr:
+		// The chain of concatenations is walked down its left side, as a
+		// wrapped asset has one for each line.
+		for expr.Op == token.ADD && isDataLiteral(expr.Y) {
+			x, ok := expr.X.(*ast.BinaryExpr)
+			if !ok {
+				return isDataLiteral(expr.X)
+			}
+			expr = x
+		}
+		return false
+	case *ast.ParenExpr:
+		return isDataLiteral(expr.X)
+	case *ast.CallExpr:
+		return isByteSlice(expr.Fun) && len(expr.Args) == 1 && isDataLiteral(expr.Args[0])
+	case *ast.CompositeLit:
+		if !isByteSlice(expr.Type) {
+			return false
+		}
+		for _, elt := range expr.Elts {
+			if lit, ok := elt.(*ast.BasicLit); !ok || lit.Kind != token.INT {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// splitGoSource reads Go source up to its end, passing it to chunk in
+// pieces which end with a blank line outside of any bracket, comment or
+// literal, the first one holding the package clause. The slice passed
+// is reused for the next piece. Along with a piece, chunk is passed the
+// offsets at which it may be cut between rows of a byte slice literal
+// into pieces of about pieceSize, see formatChunk. It returns an error at a comment or
+// literal left open, or at a bracket not matching the one it closes or
+// left open.
+func splitGoSource(r io.ByteScanner, chunk func([]byte, []int) error) error {
+	cr := &chunkReader{r: r}
+	var open []byte
+	var cuts []int
+	cut := 0
+	line := 1
+	header := true
+	for {
+		b, err := cr.ReadByte()
+		if err == io.EOF {
+			if len(open) > 0 {
+				return fmt.Errorf("line %d: unexpected EOF, expected %q", line, closing(open[len(open)-1]))
+			}
+			if len(cr.buf) > 0 {
+				return chunk(cr.buf, cuts)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch b {
+		case '\n':
+			line++
+			if len(open) == 1 && open[0] == '{' && len(cr.buf)-cut >= pieceSize && bytes.HasSuffix(cr.buf, []byte(",\n")) {
+				cut = len(cr.buf)
+				cuts = append(cuts, cut)
+			}
+			if len(open) > 0 || !bytes.HasSuffix(cr.buf, []byte("\n\n")) {
+				break
+			}
+			if header && !bytes.HasPrefix(cr.buf, []byte("package ")) && !bytes.Contains(cr.buf, []byte("\npackage ")) {
+				break
+			}
+			if err = chunk(cr.buf, cuts); err != nil {
+				return err
+			}
+			cr.buf = cr.buf[:0]
+			cuts, cut = cuts[:0], 0
+			header = false
+		case '(', '[', '{':
+			open = append(open, b)
+		case ')', ']', '}':
+			if len(open) == 0 || closing(open[len(open)-1]) != b {
+				return fmt.Errorf("line %d: unexpected %q", line, b)
+			}
+			open = open[:len(open)-1]
+		case '"', '\'', '`':
+			if err = scanGoLiteral(cr, b, &line); err != nil {
+				return err
+			}
+		case '/':
+			if err = scanGoComment(cr, &line); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// chunkReader keeps the bytes read from r since buf was last reset.
+type chunkReader struct {
+	r   io.ByteScanner
+	buf []byte
+}
+
+func (cr *chunkReader) ReadByte() (byte, error) {
+	b, err := cr.r.ReadByte()
+	if err == nil {
+		cr.buf = append(cr.buf, b)
+	}
+	return b, err
+}
+
+func (cr *chunkReader) UnreadByte() error {
+	err := cr.r.UnreadByte()
+	if err == nil {
+		cr.buf = cr.buf[:len(cr.buf)-1]
+	}
+	return err
+}
+
+// scanGoLiteral reads the rest of a literal opened by quote.
+func scanGoLiteral(r io.ByteReader, quote byte, line *int) error {
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF || (b == '\n' && quote != '`') {
+			return fmt.Errorf("line %d: literal not terminated", *line)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case b == quote:
+			return nil
+		case b == '\n':
+			*line++
+		case b == '\\' && quote != '`':
+			if _, err = r.ReadByte(); err != nil {
+				return fmt.Errorf("line %d: literal not terminated", *line)
+			}
+		}
+	}
+}
+
+// scanGoComment reads the rest of a comment, if the slash read opens one.
+func scanGoComment(r io.ByteScanner, line *int) error {
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil
+	}
+
+	switch b {
+	case '/':
+		for b != '\n' {
+			if b, err = r.ReadByte(); err != nil {
+				return nil
+			}
+		}
+		*line++
+	case '*':
+		for prev := byte(0); ; prev = b {
+			if b, err = r.ReadByte(); err != nil {
+				return fmt.Errorf("line %d: comment not terminated", *line)
+			}
+			if b == '\n' {
+				*line++
+			}
+			if prev == '*' && b == '/' {
+				break
+			}
+		}
+	default:
+		return r.UnreadByte()
+	}
+	return nil
+}
+
+// closing returns the bracket closing open.
+func closing(open byte) byte {
+	switch open {
+	case '(':
+		return ')'
+	case '[':
+		return ']'
+	}
+	return '}'
+}
@@ -0,0 +1,281 @@
+package bindata
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteGoFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sub", "bindata.go")
+	err = writeGoFile(path, func(w io.Writer) error {
+		_, err := fmt.Fprint(w, "package main\nvar x   = 1\n")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "package main\n\nvar x = 1\n"
+	if data, _ := ioutil.ReadFile(path); string(data) != expected {
+		t.Errorf("expected formatted output %q, got %q", expected, data)
+	}
+
+	failures := []func(w io.Writer) error{
+		func(w io.Writer) error {
+			fmt.Fprint(w, "package main\nvar x = ")
+			return fmt.Errorf("read error")
+		},
+		func(w io.Writer) error {
+			_, err := fmt.Fprint(w, "package main\nvar x = \n")
+			return err
+		},
+	}
+	for i, write := range failures {
+		if err := writeGoFile(path, write); err == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+		if data, _ := ioutil.ReadFile(path); string(data) != expected {
+			t.Errorf("%d: expected previous output to be kept, got %q", i, data)
+		}
+	}
+
+	list, err := ioutil.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Errorf("expected temporary files to be removed, got %d files", len(list))
+	}
+}
+
+func TestWriteFileRelative(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// The temporary file is created next to the output, not in TMPDIR,
+	// which may be on another file system.
+	tmpdir := filepath.Join(dir, "tmp")
+	if err = os.Mkdir(tmpdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv("TMPDIR", os.Getenv("TMPDIR"))
+	os.Setenv("TMPDIR", tmpdir)
+
+	err = writeGoFile("bindata.go", func(w io.Writer) error {
+		if list, _ := ioutil.ReadDir(tmpdir); len(list) != 0 {
+			t.Errorf("expected no temporary file in TMPDIR, got %s", list[0].Name())
+		}
+		_, err := fmt.Fprint(w, "package main\n")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "bindata.go")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWriteDataFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Declarations of data literals are kept as generated, the rest is
+	// formatted.
+	path := filepath.Join(dir, "bindata.go")
+	source := "package  main\n\nvar x   = []byte(\"(\" +\n\t`{`)\n\nvar y   = []byte{\n\t0x01,\n}\n\n\n\nfunc  f() {\n\n}\n"
+	err = writeDataFile(path, func(w io.Writer) error {
+		_, err := fmt.Fprint(w, source)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "package main\n\nvar x   = []byte(\"(\" +\n\t`{`)\n\nvar y   = []byte{\n\t0x01,\n}\n\nfunc f() {\n\n}\n"
+	if data, _ := ioutil.ReadFile(path); string(data) != expected {
+		t.Errorf("expected output %q, got %q", expected, data)
+	}
+
+	var chunks []string
+	err = splitGoSource(strings.NewReader("// (\n\npackage main\n\nvar x = `\n\n`\n/* {\n\n */\n\nfunc f() {\n\n}"), func(chunk []byte, cuts []int) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedChunks := []string{"// (\n\npackage main\n\n", "var x = `\n\n`\n/* {\n\n */\n\n", "func f() {\n\n}"}
+	if !reflect.DeepEqual(chunks, expectedChunks) {
+		t.Errorf("expected chunks %q, got %q", expectedChunks, chunks)
+	}
+
+	valid := []string{
+		"package main\n// (\n/* { */\nvar x = '\\''\nvar y = \"\\\"[\"\n",
+		"package main\nvar x = `\\`\nvar y = 1 / 2\n",
+	}
+	for i, src := range valid {
+		if err := splitGoSource(strings.NewReader(src), func([]byte, []int) error { return nil }); err != nil {
+			t.Errorf("%d: %v", i, err)
+		}
+	}
+
+	invalid := []string{
+		"package main\nvar x = []byte{0x01,",
+		"package main\nvar x = \"abc\n\"",
+		"package main\nvar x = `abc",
+		"package main\nvar x = (1]\n",
+		"package main\nvar x = 1)\n",
+		"package main\n/* x",
+	}
+	for i, src := range invalid {
+		if err := splitGoSource(strings.NewReader(src), func([]byte, []int) error { return nil }); err == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+	}
+
+	// The parser catches code the scanner cannot, in any declaration.
+	invalid = append(invalid, "package main\nvar x = \"a\" \"b\"\n", "package main\n\nvar x = 1\n\nvar y = `a` +\n")
+	for i, src := range invalid {
+		err = writeDataFile(path, func(w io.Writer) error {
+			_, err := fmt.Fprint(w, src)
+			return err
+		})
+		if err == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+		if data, _ := ioutil.ReadFile(path); string(data) != expected {
+			t.Errorf("%d: expected previous output to be kept, got %q", i, data)
+		}
+	}
+	if err == nil || !strings.Contains(err.Error(), ":5:") {
+		t.Errorf("expected an error at line 5, got %v", err)
+	}
+	// Long byte slice literals are parsed in pieces, with errors reported
+	// at their line all the same.
+	var long bytes.Buffer
+	fmt.Fprint(&long, "package main\n\nvar x = []byte{")
+	bw := &ByteWriter{Writer: &long}
+	for long.Len() < 3*pieceSize {
+		bw.Write([]byte("data\n"))
+	}
+	fmt.Fprint(&long, "\n}\n")
+	last := strings.Count(long.String(), "\n") - 1
+	var cuts []int
+	err = splitGoSource(bytes.NewReader(long.Bytes()), func(chunk []byte, c []int) error {
+		cuts = append(cuts, c...)
+		return nil
+	})
+	if err != nil || len(cuts) != 2 {
+		t.Errorf("expected 2 cuts, got %d (%v)", len(cuts), err)
+	}
+	err = writeDataFile(path, func(w io.Writer) error {
+		_, err := w.Write(long.Bytes())
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := ioutil.ReadFile(path); !bytes.Equal(data, long.Bytes()) {
+		t.Error("expected long literal to be kept as generated")
+	}
+	err = writeDataFile(path, func(w io.Writer) error {
+		// The last row misses a comma.
+		_, err := fmt.Fprintf(w, "%s0x01 0x02,\n}\n", bytes.TrimSuffix(long.Bytes(), []byte("\n}\n")))
+		return err
+	})
+	if line := fmt.Sprintf(":%d:", last); err == nil || !strings.Contains(err.Error(), line) {
+		t.Errorf("expected an error at line %d, got %v", last, err)
+	}
+}
+
+func TestWriteDataFileLayout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Enough data and assets to wrap literals into groups of lines, and to
+	// group the literals of packed chunks.
+	in := filepath.Join(dir, "in")
+	random := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(random)
+	files := map[string]string{
+		filepath.Join(in, "a.txt"): strings.Repeat("text with `quotes`\n", 20),
+		filepath.Join(in, "b.bin"): strings.Repeat("\x00\x01\x02\xff\n", 20),
+		filepath.Join(in, "c.bin"): string(random),
+	}
+	for i := 0; i < 2*stringGroup; i++ {
+		files[filepath.Join(in, "many", fmt.Sprintf("%d.txt", i))] = fmt.Sprintf("asset %d\n", i)
+	}
+	writeTestFiles(t, files)
+
+	// The data literals kept as generated are laid out the way gofmt
+	// lays them out.
+	configs := map[string]func(c *Config){
+		"string":       func(c *Config) {},
+		"lines":        func(c *Config) { c.Encoding = EncodingLines },
+		"bytes":        func(c *Config) { c.Encoding = EncodingBytes },
+		"uncompressed": func(c *Config) { c.Encoding = EncodingLines; c.NoCompress = true },
+		"nomemcopy":    func(c *Config) { c.Encoding = EncodingLines; c.NoMemCopy = true },
+		"packed":       func(c *Config) { c.Encoding = EncodingLines; c.Packed = true },
+		"solid":        func(c *Config) { c.Encoding = EncodingLines; c.Solid = 64 },
+		"split":        func(c *Config) { c.Encoding = EncodingBytes; c.SplitSize = 1 },
+	}
+	for name, configure := range configs {
+		c := NewConfig()
+		c.Input = []InputConfig{{Path: in}}
+		c.Output = filepath.Join(dir, "out", "bindata.go")
+		c.Width = 16
+		configure(c)
+		if err := Translate(c); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		files, _ := filepath.Glob(filepath.Join(dir, "out", "*.go"))
+		for _, file := range files {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			formatted, err := format.Source(data)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if !bytes.Equal(formatted, data) {
+				t.Errorf("%s: %s is not formatted", name, filepath.Base(file))
+			}
+		}
+		os.RemoveAll(filepath.Join(dir, "out"))
+	}
+}
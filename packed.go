@@ -0,0 +1,554 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// packedEntry locates the stored data of an asset in a chunk, or in the
+// uncompressed data of a solid group.
+type packedEntry struct {
+	chunk  int
+	group  int
+	offset int64
+	length int64
+	gzip   bool
+}
+
+// packedGroup locates the compressed data of a solid group in a chunk.
+type packedGroup struct {
+	chunk  int
+	offset int64
+	length int64
+}
+
+// packer collects the locations of the assets written to the chunks of
+// packed data, which are recorded in the table of the main output file.
+// In Assembly and Archive mode, it collects their locations in the
+// archive, whose size and ID are recorded as well.
+type packer struct {
+	toc     []Asset
+	index   map[string]int
+	entries []packedEntry
+	groups  []packedGroup
+	size    int64
+	id      string
+}
+
+// packAssets prepares the assets for packed mode. Each asset is served by
+// the load method of its entry in the table of packed assets, which is
+// recorded in Func. Aliases refer to the entry of their target.
+func packAssets(toc []Asset) {
+	tableFuncs(toc, "_bindataPacked")
+}
+
+// tableFuncs makes the load method of the i-th entry of the named table
+// the function of the i-th asset, and updates aliases accordingly.
+func tableFuncs(toc []Asset, table string) {
+	funcs := make(map[string]string, len(toc))
+	for i := range toc {
+		funcs[toc[i].Func] = fmt.Sprintf("%s[%d].load", table, i)
+	}
+
+	for i := range toc {
+		toc[i].Func = funcs[toc[i].Func]
+		if len(toc[i].Alias) > 0 {
+			toc[i].Alias = funcs[toc[i].Alias]
+		}
+	}
+}
+
+// newPacker returns a packer for the given, packed, assets.
+func newPacker(toc []Asset) *packer {
+	p := &packer{
+		toc:     toc,
+		index:   make(map[string]int, len(toc)),
+		entries: make([]packedEntry, len(toc)),
+	}
+	for i := range toc {
+		p.index[toc[i].Func] = i
+	}
+	return p
+}
+
+// countWriter counts the bytes written to it.
+type countWriter struct {
+	io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// writeChunk writes the stored data of the given assets, one after the
+// other, as the string constant _bindataChunk<n>. Each asset or solid
+// group starts on a line of its own. The chunk ends with the index and
+// footer of an archive, by which ReadBinary finds the assets in a built
+// executable.
+func (p *packer) writeChunk(w io.Writer, c *Config, toc []Asset, n int) error {
+	_, err := fmt.Fprintf(w, "var _bindataChunk%d = \"\"", n)
+	if err != nil {
+		return err
+	}
+
+	groups, err := solidGroups(c, toc)
+	if err != nil {
+		return err
+	}
+
+	// Like the lines of a StringWriter, the literals are concatenated in
+	// parenthesized groups to keep the chain of + operators short. The
+	// index is written as the last literal.
+	depth := func(i int) int {
+		if i < stringGroup {
+			return 0
+		}
+		return 1
+	}
+	sep := func(i int) error {
+		sep := " +\n\t"
+		switch {
+		case i > stringGroup && i%stringGroup != 0:
+			sep = " +\n\t\t"
+		case i == stringGroup:
+			sep = " +\n\t("
+		case i > stringGroup && i%stringGroup == 0:
+			sep = ") +\n\t("
+		}
+		_, err := fmt.Fprint(w, sep)
+		return err
+	}
+
+	h := sha256.New()
+	stored := make(map[string]bool)
+	var offset int64
+	for i, group := range groups {
+		if err = sep(i); err != nil {
+			return err
+		}
+
+		if len(group) == 1 {
+			asset := group[0]
+			entry := packedEntry{chunk: n, group: -1, offset: offset, gzip: !c.NoCompress}
+			if entry.length, err = writePackedData(w, h, c, asset, depth(i)); err != nil {
+				return err
+			}
+
+			p.entries[p.index[asset.Func]] = entry
+			stored[asset.Func] = true
+			offset += entry.length
+			continue
+		}
+
+		length, err := writeSolidGroup(w, h, c, group, depth(i))
+		if err != nil {
+			return err
+		}
+
+		var member int64
+		for _, asset := range group {
+			entry := packedEntry{group: len(p.groups), offset: member}
+			if entry.length, err = asset.dataSize(); err != nil {
+				return err
+			}
+
+			p.entries[p.index[asset.Func]] = entry
+			stored[asset.Func] = true
+			member += entry.length
+		}
+
+		p.groups = append(p.groups, packedGroup{chunk: n, offset: offset, length: length})
+		offset += length
+	}
+
+	if err = sep(len(groups)); err != nil {
+		return err
+	}
+	dw, end, err := openData(w, c, false, depth(len(groups)))
+	if err != nil {
+		return err
+	}
+	cw := &countWriter{Writer: io.MultiWriter(dw, h), n: offset}
+	if err = p.writeIndex(dw, cw, h, c, stored); err != nil {
+		return err
+	}
+	if err = end(); err != nil {
+		return err
+	}
+
+	if len(groups)+1 > stringGroup {
+		if _, err = fmt.Fprint(w, ")"); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprint(w, "\n\n")
+	return err
+}
+
+// writePackedData writes the stored data of an asset as a string literal,
+// which is hashed into h as well, and returns its length. The literal is
+// nested in depth parenthesized groups.
+func writePackedData(w, h io.Writer, c *Config, asset *Asset, depth int) (int64, error) {
+	fd, err := openAsset(asset)
+	if err != nil {
+		return 0, err
+	}
+
+	defer fd.Close()
+
+	if c.NoCompress {
+		cw := &countWriter{Writer: h}
+		err = writeLiteral(w, c, asset, io.TeeReader(fd, cw), false, depth)
+		return cw.n, err
+	}
+
+	dw, end, err := openData(w, c, false, depth)
+	if err != nil {
+		return 0, err
+	}
+
+	cw := &countWriter{Writer: io.MultiWriter(dw, h)}
+	if err = compress(cw, c, asset, fd); err != nil {
+		return 0, err
+	}
+
+	return cw.n, end()
+}
+
+// solidGroups returns the assets storing their contents, in groups which
+// are compressed as one stream. Consecutive assets smaller than c.Solid
+// form a group, as long as their total size does not exceed c.Solid.
+// Any other asset forms a group of its own.
+func solidGroups(c *Config, toc []Asset) ([][]*Asset, error) {
+	var groups [][]*Asset
+	var group []*Asset
+	var size int64
+
+	for i := range toc {
+		asset := &toc[i]
+		if len(asset.Alias) > 0 {
+			continue
+		}
+
+		n, err := asset.dataSize()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(group) > 0 && size+n > c.Solid {
+			groups = append(groups, group)
+			group, size = nil, 0
+		}
+		if n >= c.Solid {
+			groups = append(groups, []*Asset{asset})
+			continue
+		}
+
+		group = append(group, asset)
+		size += n
+	}
+
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// writeSolidGroup writes the contents of the assets as one compressed
+// string literal, which is hashed into h as well, and returns its length.
+// The literal is nested in depth parenthesized groups.
+func writeSolidGroup(w, h io.Writer, c *Config, group []*Asset, depth int) (int64, error) {
+	readers := make([]io.Reader, len(group))
+	for i, asset := range group {
+		readers[i] = &assetReader{asset: asset}
+	}
+
+	// The compressed group is cached under the hash of its members.
+	solid := &Asset{}
+	if len(c.Cache) > 0 {
+		sum := sha256.New()
+		for _, asset := range group {
+			if err := asset.hash(); err != nil {
+				return 0, err
+			}
+			fmt.Fprintf(sum, "%s\n", asset.Hash)
+		}
+		solid.Hash = hex.EncodeToString(sum.Sum(nil))
+	}
+
+	dw, end, err := openData(w, c, false, depth)
+	if err != nil {
+		return 0, err
+	}
+
+	cw := &countWriter{Writer: io.MultiWriter(dw, h)}
+	if err = compress(cw, c, solid, io.MultiReader(readers...)); err != nil {
+		return 0, err
+	}
+
+	return cw.n, end()
+}
+
+// assetReader reads the contents of an asset, opening it on first read
+// and closing it at the end.
+type assetReader struct {
+	asset *Asset
+	rc    io.ReadCloser
+}
+
+func (r *assetReader) Read(p []byte) (int, error) {
+	if r.rc == nil {
+		rc, err := openAsset(r.asset)
+		if err != nil {
+			return 0, err
+		}
+		r.rc = rc
+	}
+
+	n, err := r.rc.Read(p)
+	if err == io.EOF {
+		r.rc.Close()
+	}
+	return n, err
+}
+
+// writePacked writes the release code of packed mode. Unless the chunks
+// are written to shards, all data is written as a single chunk.
+func writePacked(w io.Writer, c *Config, toc []Asset, p *packer, sharded bool) error {
+	err := writePackedHeader(w, c)
+	if err != nil {
+		return err
+	}
+
+	err = writeAssetFS(w, c)
+	if err != nil {
+		return err
+	}
+
+	if !sharded {
+		if err = p.writeChunk(w, c, toc, 0); err != nil {
+			return err
+		}
+	}
+
+	return p.writeTable(w, c, toc)
+}
+
+// writePackedHeader writes the imports and the types and functions
+// reading packed assets.
+func writePackedHeader(w io.Writer, c *Config) error {
+	imports := []string{"fmt", "io/ioutil", "os", "path/filepath", "strings", "time"}
+	if c.HttpFileSystem {
+		imports = append(imports, "bytes", "net/http")
+	}
+
+	unpack := `	return []byte(data), nil`
+	if c.NoMemCopy {
+		imports = append(imports, "reflect", "unsafe")
+		unpack = `	var empty [0]byte
+	sx := (*reflect.StringHeader)(unsafe.Pointer(&data))
+	b := empty[:]
+	bx := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	bx.Data = sx.Data
+	bx.Len = len(data)
+	bx.Cap = bx.Len
+	return b, nil`
+	}
+
+	if !c.NoCompress && !c.NoUnpack {
+		imports = append(imports, "compress/gzip", "io")
+		unpack = `	if compressed {
+		return bindataGunzip(strings.NewReader(data), name)
+	}
+
+` + unpack
+	}
+
+	unpackChunk := `bindataUnpack((*p.chunk)[p.offset:p.offset+p.length], p.gzip, p.name)`
+	load := `	bytes, err := ` + unpackChunk
+	groupField, group := "", ""
+	if c.Solid > 0 {
+		imports = append(imports, "sync")
+		groupField = "\n\tgroup   *bindataGroup"
+		load = `	var bytes []byte
+	var err error
+	if p.group != nil {
+		bytes, err = p.group.load(p.offset, p.length)
+	} else {
+		bytes, err = ` + unpackChunk + `
+	}`
+
+		// Unless read-only slices are fine, the cached data is copied.
+		member := `	b := make([]byte, length)
+	copy(b, g.data[offset:offset+length])
+	return b, nil`
+		if c.NoMemCopy {
+			member = `	return g.data[offset : offset+length : offset+length], nil`
+		}
+
+		group = fmt.Sprintf(`
+// bindataGroup locates a group of assets compressed as one stream,
+// which is decompressed on first access and kept in memory.
+type bindataGroup struct {
+	chunk  *string
+	offset int
+	length int
+	once   sync.Once
+	data   []byte
+	err    error
+}
+
+func (g *bindataGroup) load(offset, length int) ([]byte, error) {
+	g.once.Do(func() {
+		g.data, g.err = bindataUnpack((*g.chunk)[g.offset:g.offset+g.length], true, "solid group")
+	})
+	if g.err != nil {
+		return nil, g.err
+	}
+
+%s
+}
+`, member)
+	}
+
+	_, err := fmt.Fprintf(w, `import (
+	"%s"
+)
+
+// bindataPacked locates the stored data of an asset in a chunk of
+// packed data, along with its info.
+type bindataPacked struct {
+	chunk   *string%s
+	offset  int
+	length  int
+	gzip    bool
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime int64
+	link    string
+}
+
+func (p *bindataPacked) load() (*asset, error) {
+%s
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: p.name, size: p.size, mode: p.mode, modTime: time.Unix(p.modTime, 0)}
+	a := &asset{bytes: bytes, info: info, link: p.link}
+	return a, nil
+}
+
+func bindataUnpack(data string, compressed bool, name string) ([]byte, error) {
+%s
+}
+%s
+`, strings.Join(imports, "\"\n\t\""), groupField, load, unpack, group)
+	if err != nil {
+		return err
+	}
+
+	if err = writeGunzip(w, c); err != nil {
+		return err
+	}
+	return header_release_common(w)
+}
+
+// writeGunzip writes bindataGunzip, which decompresses the data of an
+// asset, unless the data is stored uncompressed or left compressed. The
+// code of Packed, Assembly and Archive mode shares it.
+func writeGunzip(w io.Writer, c *Config) error {
+	if c.NoCompress || c.NoUnpack {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(w, `func bindataGunzip(r io.Reader, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read %%q: %%v", name, err)
+	}
+
+	data, err := ioutil.ReadAll(gz)
+	clErr := gz.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("read %%q: %%v", name, err)
+	}
+	if clErr != nil {
+		return nil, fmt.Errorf("read %%q: %%v", name, clErr)
+	}
+
+	return data, nil
+}
+
+`)
+	return err
+}
+
+// writeTable writes the table of packed assets.
+func (p *packer) writeTable(w io.Writer, c *Config, toc []Asset) error {
+	if c.Solid > 0 {
+		_, err := fmt.Fprintf(w, `// _bindataGroups is a table, holding the location of each solid group.
+var _bindataGroups = [...]bindataGroup{
+`)
+		if err != nil {
+			return err
+		}
+
+		for _, group := range p.groups {
+			_, err = fmt.Fprintf(w, "\t{chunk: &_bindataChunk%d, offset: %d, length: %d},\n", group.chunk, group.offset, group.length)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err = writeTOCFooter(w); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, `// _bindataPacked is a table, holding the location and info of each asset.
+var _bindataPacked = [...]bindataPacked{
+`)
+	if err != nil {
+		return err
+	}
+
+	for i := range toc {
+		asset := &toc[i]
+		size, mode, modTime, err := asset.metadata(c)
+		if err != nil {
+			return err
+		}
+
+		entry := p.entries[i]
+		if len(asset.Alias) > 0 {
+			entry = p.entries[p.index[asset.Alias]]
+		}
+
+		location := fmt.Sprintf("chunk: &_bindataChunk%d", entry.chunk)
+		if entry.group >= 0 {
+			location = fmt.Sprintf("group: &_bindataGroups[%d]", entry.group)
+		}
+
+		_, err = fmt.Fprintf(w, "\t{%s, offset: %d, length: %d, gzip: %v, name: %q, size: %d, mode: os.FileMode(%d), modTime: %d, link: %q},\n",
+			location, entry.offset, entry.length, entry.gzip, asset.Name, size, mode, modTime, asset.Link)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeTOCFooter(w)
+}
@@ -0,0 +1,161 @@
+package bindata
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackAssets(t *testing.T) {
+	toc := []Asset{
+		{Name: "a", Func: "a"},
+		{Name: "b", Func: "b"},
+		{Name: "link", Func: "link", Alias: "b"},
+	}
+	packAssets(toc)
+
+	expected := []string{"_bindataPacked[0].load", "_bindataPacked[1].load", "_bindataPacked[2].load"}
+	for i := range toc {
+		if toc[i].Func != expected[i] {
+			t.Errorf("%s: expected %q, got %q", toc[i].Name, expected[i], toc[i].Func)
+		}
+	}
+	if toc[2].Alias != "_bindataPacked[1].load" {
+		t.Errorf("expected alias of the packed entry of b, got %q", toc[2].Alias)
+	}
+}
+
+func TestTranslatePacked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	if err := os.Mkdir(in, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{"a.txt": "first", "b.txt": "second `text`", "c.txt": ""}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(in, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true}}
+	c.Prefix = in
+	c.Output = filepath.Join(dir, "bindata.go")
+	c.Packed = true
+	c.NoCompress = true
+
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(c.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+
+	for _, s := range []string{
+		"var _bindataChunk0 = \"\" +\n\t`first` +\n\t`second ` + \"`\" + `text` + \"`\" + ``",
+		`{chunk: &_bindataChunk0, offset: 0, length: 5, gzip: false, name: "a.txt",`,
+		`{chunk: &_bindataChunk0, offset: 5, length: 13, gzip: false, name: "b.txt",`,
+		`{chunk: &_bindataChunk0, offset: 18, length: 0, gzip: false, name: "c.txt",`,
+		`"b.txt": _bindataPacked[1].load,`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected output to contain %q", s)
+		}
+	}
+	if strings.Contains(out, "func aTxt") {
+		t.Errorf("expected no functions per asset")
+	}
+}
+
+func TestTranslatePackedGroups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	if err := os.Mkdir(in, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2*stringGroup+1; i++ {
+		name := filepath.Join(in, fmt.Sprintf("%03d.txt", i))
+		if err := ioutil.WriteFile(name, []byte(fmt.Sprint(i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true}}
+	c.Prefix = in
+	c.Output = filepath.Join(dir, "bindata.go")
+	c.Packed = true
+	c.NoCompress = true
+
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(c.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+
+	// The literals past the first stringGroup are concatenated in groups.
+	for _, s := range []string{"`63` +\n\t(`64` +", "`127`) +\n\t(`128` +\n\t\t\""} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected output to contain %q", s)
+		}
+	}
+}
+
+func TestSolidGroups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sizes := []int{10, 20, 30, 100, 5, 5, 40}
+	var toc []Asset
+	for i, size := range sizes {
+		path := filepath.Join(dir, string(rune('a'+i)))
+		if err := ioutil.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		toc = append(toc, Asset{Path: path, Name: filepath.Base(path), Func: filepath.Base(path)})
+	}
+	toc[5].Alias = "a"
+
+	c := NewConfig()
+	c.Solid = 50
+	groups, err := solidGroups(c, toc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, group := range groups {
+		var groupNames []string
+		for _, asset := range group {
+			groupNames = append(groupNames, asset.Name)
+		}
+		names = append(names, strings.Join(groupNames, ""))
+	}
+
+	expected := []string{"ab", "c", "d", "eg"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected groups %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,874 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GeneratedAsset is an asset read back from generated code.
+type GeneratedAsset struct {
+	// Name is the name of the asset in the table of contents.
+	Name string
+
+	// Func is the function serving the asset in the generated code.
+	Func string
+
+	// Data holds the contents of the asset, as returned by Asset.
+	Data []byte
+
+	// Size, Mode and ModTime hold the recorded info of the asset, which
+	// are zero if none was recorded.
+	Size    int64
+	Mode    os.FileMode
+	ModTime int64
+
+	// Link holds the target of a preserved symbolic link.
+	Link string
+
+	// Archived is set when the data of the asset is kept in an archive
+	// written with -archive, rather than in the generated code. Data is
+	// then empty.
+	Archived bool
+}
+
+// Generated holds what was read from the source of a release build
+// generated by go-bindata.
+type Generated struct {
+	// Package is the name of the package of the generated code.
+	Package string
+
+	// Tags holds the build tags of the `// +build` line, if any.
+	Tags string
+
+	// Funcs lists the exported functions declared by the generated code.
+	Funcs []string
+
+	// Assets holds the assets in the order of the table of contents.
+	Assets []GeneratedAsset
+
+	// Dirs holds the recorded directories, with their modes and
+	// modification times, in the order of the directory table. Code
+	// generated by earlier versions records none.
+	Dirs []GeneratedAsset
+
+	// OutsideLinks, when set, lets Restore recreate preserved links whose
+	// target is absolute or leaves the directory restored to.
+	OutsideLinks bool
+
+	decls map[string]*ast.FuncDecl
+}
+
+// declares reports whether the generated code declares the function.
+func (g *Generated) declares(name string) bool {
+	_, ok := g.decls[name]
+	return ok
+}
+
+// ReadGenerated reads the assets from a Go file generated by go-bindata,
+// along with its shards, without compiling it. Release builds of current
+// and earlier versions are supported, in the compressed and uncompressed,
+// memcopy and nomemcopy variants, as well as packed, embed and assembly
+// output. Of archive output, only the info of the assets is read. Debug
+// builds embed no data and cannot be read.
+func ReadGenerated(filename string) (*Generated, error) {
+	files := []string{filename}
+	for n := 0; isShard(shardPath(filename, n)); n++ {
+		files = append(files, shardPath(filename, n))
+	}
+
+	r := &sourceReader{
+		dir:    filepath.Dir(filename),
+		asm:    asmPath(filename),
+		funcs:  make(map[string]*ast.FuncDecl),
+		vars:   make(map[string]ast.Expr),
+		data:   make(map[string][]byte),
+		groups: make(map[int][]byte),
+	}
+	g := &Generated{decls: r.funcs}
+
+	fset := token.NewFileSet()
+	for i, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			g.Package = f.Name.Name
+			g.Tags = buildTags(f)
+		}
+
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv != nil {
+					continue
+				}
+				r.funcs[decl.Name.Name] = decl
+				if decl.Name.IsExported() {
+					g.Funcs = append(g.Funcs, decl.Name.Name)
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					spec := spec.(*ast.ValueSpec)
+					for j, name := range spec.Names {
+						if j < len(spec.Values) {
+							r.vars[name.Name] = spec.Values[j]
+						}
+					}
+				}
+			}
+		}
+	}
+
+	toc, ok := r.vars["_bindata"].(*ast.CompositeLit)
+	if !ok {
+		return nil, fmt.Errorf("%s: no table of contents found", filename)
+	}
+
+	for _, elt := range toc.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, fmt.Errorf("%s: unexpected entry in the table of contents", filename)
+		}
+		name, ok := stringValue(kv.Key)
+		if !ok {
+			return nil, fmt.Errorf("%s: unexpected asset name in the table of contents", filename)
+		}
+
+		var asset GeneratedAsset
+		var err error
+		switch fn := kv.Value.(type) {
+		case *ast.Ident:
+			asset, err = r.funcAsset(fn.Name)
+		case *ast.SelectorExpr:
+			asset, err = r.tableAsset(fn)
+		default:
+			err = fmt.Errorf("unexpected function")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: asset %q: %v", filename, name, err)
+		}
+
+		asset.Name = name
+		g.Assets = append(g.Assets, asset)
+	}
+
+	if dirs, ok := r.vars["_bindataDirs"].(*ast.CompositeLit); ok {
+		for _, elt := range dirs.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				return nil, fmt.Errorf("%s: unexpected entry in the directory table", filename)
+			}
+			name, ok := stringValue(kv.Key)
+			info, isLit := kv.Value.(*ast.CompositeLit)
+			if !ok || !isLit {
+				return nil, fmt.Errorf("%s: unexpected entry in the directory table", filename)
+			}
+
+			fields := literalFields(info)
+			dir := GeneratedAsset{Name: name}
+			mode, _ := intValue(fields["mode"])
+			dir.Mode = os.FileMode(mode)
+			dir.ModTime, _ = intValue(fields["modTime"])
+			g.Dirs = append(g.Dirs, dir)
+		}
+	}
+
+	return g, nil
+}
+
+// Asset returns the asset with the given name, or nil if there is none.
+func (g *Generated) Asset(name string) *GeneratedAsset {
+	for i := range g.Assets {
+		if g.Assets[i].Name == name {
+			return &g.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Restore writes the assets below dir, recreating the recorded
+// directories, even empty ones, and preserved links, and applying the
+// recorded modes and modification times. Files without a recorded mode
+// are written with mode 0644. Absolute asset names are restored relative
+// to dir. Nothing is written through a symbolic link below dir, and
+// unless OutsideLinks is set, links pointing outside of dir are refused.
+func (g *Generated) Restore(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for i := range g.Dirs {
+		name, err := restoreName(g.Dirs[i].Name)
+		if err != nil {
+			return fmt.Errorf("cannot restore directory %q: %v", g.Dirs[i].Name, err)
+		}
+		if err = mkdirNoLinks(dir, name); err != nil {
+			return fmt.Errorf("cannot restore directory %q: %v", g.Dirs[i].Name, err)
+		}
+	}
+
+	for i := range g.Assets {
+		asset := &g.Assets[i]
+		name, err := restoreName(asset.Name)
+		if err != nil {
+			return fmt.Errorf("cannot restore asset %q: %v", asset.Name, err)
+		}
+
+		if err := mkdirNoLinks(dir, path.Dir(name)); err != nil {
+			return fmt.Errorf("cannot restore asset %q: %v", asset.Name, err)
+		}
+
+		// An existing link is replaced rather than written through.
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if fi, err := os.Lstat(file); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+			if err = os.Remove(file); err != nil {
+				return err
+			}
+		}
+
+		if len(asset.Link) == 0 && asset.Archived {
+			return fmt.Errorf("cannot restore asset %q: the data is kept in an archive, see extract-binary", asset.Name)
+		}
+
+		if len(asset.Link) > 0 {
+			if !g.OutsideLinks && linkLeaves(dir, name, asset.Link) {
+				return fmt.Errorf("cannot restore asset %q: link to %s leaves the directory", asset.Name, asset.Link)
+			}
+			os.Remove(file)
+			if err := os.Symlink(asset.Link, file); err != nil {
+				return err
+			}
+			continue
+		}
+
+		mode := asset.Mode.Perm()
+		if mode == 0 {
+			mode = 0644
+		}
+		if err := ioutil.WriteFile(file, asset.Data, mode); err != nil {
+			return err
+		}
+		if err := os.Chmod(file, mode); err != nil {
+			return err
+		}
+
+		modTime := time.Unix(asset.ModTime, 0)
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			return err
+		}
+	}
+
+	// Directories get their info once their contents are written, the
+	// deepest ones first.
+	for i := len(g.Dirs) - 1; i >= 0; i-- {
+		name, _ := restoreName(g.Dirs[i].Name)
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if mode := g.Dirs[i].Mode.Perm(); mode != 0 {
+			if err := os.Chmod(file, mode); err != nil {
+				return err
+			}
+		}
+		modTime := time.Unix(g.Dirs[i].ModTime, 0)
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreName returns the slash separated name an asset or directory is
+// restored to, relative to the directory restored to.
+func restoreName(name string) (string, error) {
+	rel := strings.TrimPrefix(name, "/")
+	if clean := path.Clean("/" + rel); rel == "" || clean[1:] != rel {
+		return "", fmt.Errorf("invalid name")
+	}
+	return rel, nil
+}
+
+// mkdirNoLinks creates the directories of the slash separated path rel
+// below dir. Unlike os.MkdirAll, it fails on a symbolic link instead of
+// following it.
+func mkdirNoLinks(dir, rel string) error {
+	if rel == "." {
+		return nil
+	}
+
+	p := dir
+	for _, elem := range strings.Split(rel, "/") {
+		p = filepath.Join(p, elem)
+		fi, err := os.Lstat(p)
+		switch {
+		case os.IsNotExist(err):
+			if err = os.Mkdir(p, 0755); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case fi.Mode()&os.ModeSymlink != 0:
+			return fmt.Errorf("%s is a symbolic link", p)
+		case !fi.IsDir():
+			return fmt.Errorf("%s is not a directory", p)
+		}
+	}
+	return nil
+}
+
+// linkLeaves reports whether the target of the link with the given slash
+// separated name is absolute or leads outside of dir, following the links
+// restored so far. It is generated as _linkLeaves by linkLeavesCode, which
+// must stay the same.
+func linkLeaves(dir, name, target string) bool {
+	target = filepath.ToSlash(target)
+	if filepath.IsAbs(target) || strings.HasPrefix(target, "/") {
+		return true
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return true
+	}
+	p, err := filepath.EvalSymlinks(filepath.Join(dir, filepath.Dir(filepath.FromSlash(name))))
+	if err != nil {
+		return true
+	}
+	exists := true
+	for _, elem := range strings.Split(target, "/") {
+		switch elem {
+		case "", ".":
+		case "..":
+			// Where a directory not restored yet leads back to is unknown,
+			// it may still become a link.
+			if !exists {
+				return true
+			}
+			p = filepath.Dir(p)
+		default:
+			p = filepath.Join(p, elem)
+			if real, err := filepath.EvalSymlinks(p); err == nil {
+				p = real
+			} else {
+				exists = false
+			}
+		}
+	}
+	rel, err := filepath.Rel(root, p)
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// sourceReader evaluates the declarations of generated code statically.
+type sourceReader struct {
+	dir    string
+	funcs  map[string]*ast.FuncDecl
+	vars   map[string]ast.Expr
+	data   map[string][]byte
+	groups map[int][]byte
+	asm    string
+	asmBuf []byte
+}
+
+// funcAsset reads an asset served by its own function, which calls a
+// function returning the contents of a data variable, possibly through
+// a function decompressing it.
+func (r *sourceReader) funcAsset(name string) (GeneratedAsset, error) {
+	asset := GeneratedAsset{Func: name}
+
+	fn, ok := r.funcs[name]
+	if !ok || fn.Body == nil {
+		return asset, fmt.Errorf("function %s not found", name)
+	}
+
+	var err error
+	var found bool
+	if asset.Data, found, err = r.funcData(name, 0); err != nil {
+		return asset, err
+	}
+	if !found {
+		return asset, fmt.Errorf("no embedded data found, not a release build?")
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		switch typ, _ := lit.Type.(*ast.Ident); {
+		case typ == nil:
+		case typ.Name == "bindataFileInfo":
+			fields := literalFields(lit)
+			asset.Size, _ = intValue(fields["size"])
+			mode, _ := intValue(fields["mode"])
+			asset.Mode = os.FileMode(mode)
+			asset.ModTime, _ = intValue(fields["modTime"])
+		case typ.Name == "asset":
+			asset.Link, _ = stringValue(literalFields(lit)["link"])
+		}
+		return true
+	})
+
+	return asset, nil
+}
+
+// funcData returns the contents returned by the named function, following
+// the calls it makes until a data variable is found. Data passed to a
+// function using gzip is decompressed.
+func (r *sourceReader) funcData(name string, depth int) (data []byte, found bool, err error) {
+	fn, ok := r.funcs[name]
+	if !ok || fn.Body == nil || depth > 4 {
+		return nil, false, nil
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if found || err != nil {
+			return false
+		}
+
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			callee, ok := n.Fun.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			for _, arg := range n.Args {
+				if data, found = r.dataVar(arg); found {
+					if r.usesGzip(callee.Name) {
+						data, err = gunzip(data)
+					}
+					return false
+				}
+			}
+			data, found, err = r.funcData(callee.Name, depth+1)
+		case *ast.Ident:
+			data, found = r.dataVar(n)
+		}
+		return !found && err == nil
+	})
+
+	return
+}
+
+// tableAsset reads an asset served by the load method of an entry of the
+// table of packed or embedded assets.
+func (r *sourceReader) tableAsset(fn *ast.SelectorExpr) (GeneratedAsset, error) {
+	var asset GeneratedAsset
+
+	index, ok := fn.X.(*ast.IndexExpr)
+	if !ok || fn.Sel.Name != "load" {
+		return asset, fmt.Errorf("unexpected function")
+	}
+	table, ok := index.X.(*ast.Ident)
+	if !ok {
+		return asset, fmt.Errorf("unexpected function")
+	}
+	asset.Func = table.Name + "[" + indexString(index.Index) + "].load"
+
+	fields, err := r.tableEntry(table.Name, index.Index)
+	if err != nil {
+		return asset, err
+	}
+
+	asset.Size, _ = intValue(fields["size"])
+	mode, _ := intValue(fields["mode"])
+	asset.Mode = os.FileMode(mode)
+	asset.ModTime, _ = intValue(fields["modTime"])
+	asset.Link, _ = stringValue(fields["link"])
+
+	switch table.Name {
+	case "_bindataPacked":
+		asset.Data, err = r.packedData(fields)
+	case "_bindataAsm":
+		asset.Data, err = r.asmEntryData(fields)
+	case "_bindataArchived":
+		asset.Archived = true
+	case "_bindataEmbedded":
+		if file, _ := stringValue(fields["path"]); len(file) > 0 {
+			asset.Data, err = ioutil.ReadFile(filepath.Join(r.dir, filepath.FromSlash(file)))
+		}
+	default:
+		err = fmt.Errorf("unknown table %s", table.Name)
+	}
+
+	return asset, err
+}
+
+// tableEntry returns the fields of an entry of the named table.
+func (r *sourceReader) tableEntry(table string, index ast.Expr) (map[string]ast.Expr, error) {
+	lit, ok := r.vars[table].(*ast.CompositeLit)
+	if !ok {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+
+	i, ok := intValue(index)
+	if !ok || i < 0 || i >= int64(len(lit.Elts)) {
+		return nil, fmt.Errorf("entry %s[%s] not found", table, indexString(index))
+	}
+
+	entry, ok := lit.Elts[i].(*ast.CompositeLit)
+	if !ok {
+		return nil, fmt.Errorf("unexpected entry %s[%d]", table, i)
+	}
+
+	return literalFields(entry), nil
+}
+
+// packedData returns the contents of a packed asset, stored in a chunk or
+// in the data of a solid group.
+func (r *sourceReader) packedData(fields map[string]ast.Expr) ([]byte, error) {
+	offset, _ := intValue(fields["offset"])
+	length, _ := intValue(fields["length"])
+
+	if group, ok := fields["group"]; ok {
+		index, ok := unaryOperand(group).(*ast.IndexExpr)
+		if !ok {
+			return nil, fmt.Errorf("unexpected group")
+		}
+		i, _ := intValue(index.Index)
+		data, err := r.groupData(int(i))
+		if err != nil {
+			return nil, err
+		}
+		return sliceData(data, offset, length)
+	}
+
+	data, ok := r.dataVar(unaryOperand(fields["chunk"]))
+	if !ok {
+		return nil, fmt.Errorf("chunk not found")
+	}
+	data, err := sliceData(data, offset, length)
+	if err != nil {
+		return nil, err
+	}
+
+	if compressed, _ := fields["gzip"].(*ast.Ident); compressed != nil && compressed.Name == "true" && r.usesGzip("bindataUnpack") {
+		return gunzip(data)
+	}
+	return data, nil
+}
+
+// asmEntryData returns the contents of an asset stored in the data of an
+// assembly file.
+func (r *sourceReader) asmEntryData(fields map[string]ast.Expr) ([]byte, error) {
+	if r.asmBuf == nil {
+		data, err := readAsmData(r.asm)
+		if err != nil {
+			return nil, err
+		}
+		r.asmBuf = data
+	}
+
+	offset, _ := intValue(fields["offset"])
+	length, _ := intValue(fields["length"])
+	data, err := sliceData(r.asmBuf, offset, length)
+	if err != nil {
+		return nil, err
+	}
+
+	if compressed, _ := fields["gzip"].(*ast.Ident); compressed != nil && compressed.Name == "true" && r.usesGzip("bindataUnpack") {
+		return gunzip(data)
+	}
+	return data, nil
+}
+
+// readAsmData reads the data of the symbol _bindataData defined by the
+// DATA directives of an assembly file.
+func readAsmData(path string) ([]byte, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []byte{}
+	for _, line := range strings.Split(string(src), "\n") {
+		if !strings.HasPrefix(line, "DATA ·_bindataData+") {
+			continue
+		}
+
+		var offset, length int
+		_, err = fmt.Sscanf(strings.TrimPrefix(line, "DATA ·_bindataData+"), "%d(SB)/%d,", &offset, &length)
+		if err != nil {
+			return nil, fmt.Errorf("%s: unexpected directive %q", path, line)
+		}
+		value, err := strconv.Unquote(line[strings.Index(line, "$")+1:])
+		if err != nil || len(value) != length {
+			return nil, fmt.Errorf("%s: unexpected directive %q", path, line)
+		}
+
+		if n := offset + length; n > len(data) {
+			data = append(data, make([]byte, n-len(data))...)
+		}
+		copy(data[offset:], value)
+	}
+
+	return data, nil
+}
+
+// groupData returns the decompressed data of the i-th solid group.
+func (r *sourceReader) groupData(i int) ([]byte, error) {
+	if data, ok := r.groups[i]; ok {
+		return data, nil
+	}
+
+	fields, err := r.tableEntry("_bindataGroups", &ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(i)})
+	if err != nil {
+		return nil, err
+	}
+
+	offset, _ := intValue(fields["offset"])
+	length, _ := intValue(fields["length"])
+	data, ok := r.dataVar(unaryOperand(fields["chunk"]))
+	if !ok {
+		return nil, fmt.Errorf("chunk of group %d not found", i)
+	}
+	if data, err = sliceData(data, offset, length); err != nil {
+		return nil, err
+	}
+	if data, err = gunzip(data); err != nil {
+		return nil, err
+	}
+
+	r.groups[i] = data
+	return data, nil
+}
+
+// dataVar returns the value of the data variable the expression refers to.
+func (r *sourceReader) dataVar(expr ast.Expr) ([]byte, bool) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	if data, ok := r.data[ident.Name]; ok {
+		return data, true
+	}
+
+	value, ok := r.vars[ident.Name]
+	if !ok {
+		return nil, false
+	}
+	data, ok := dataValue(value)
+	if ok {
+		r.data[ident.Name] = data
+	}
+	return data, ok
+}
+
+// usesGzip reports whether the named function decompresses with gzip,
+// itself or through the functions of the file it calls.
+func (r *sourceReader) usesGzip(name string) bool {
+	return r.callsGzip(name, make(map[string]bool))
+}
+
+// callsGzip is usesGzip, skipping the functions already visited.
+func (r *sourceReader) callsGzip(name string, visited map[string]bool) bool {
+	fn, ok := r.funcs[name]
+	if !ok || fn.Body == nil || visited[name] {
+		return false
+	}
+	visited[name] = true
+
+	uses := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if pkg, ok := n.X.(*ast.Ident); ok && pkg.Name == "gzip" {
+				uses = true
+			}
+		case *ast.CallExpr:
+			if callee, ok := n.Fun.(*ast.Ident); ok && r.callsGzip(callee.Name, visited) {
+				uses = true
+			}
+		}
+		return !uses
+	})
+	return uses
+}
+
+// dataValue evaluates a string or byte slice literal, possibly made of
+// several concatenated strings.
+func dataValue(expr ast.Expr) ([]byte, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit, *ast.BinaryExpr, *ast.ParenExpr:
+		s, ok := stringValue(expr)
+		return []byte(s), ok
+	case *ast.CallExpr:
+		if !isByteSlice(expr.Fun) || len(expr.Args) != 1 {
+			return nil, false
+		}
+		s, ok := stringValue(expr.Args[0])
+		return []byte(s), ok
+	case *ast.CompositeLit:
+		if !isByteSlice(expr.Type) {
+			return nil, false
+		}
+		data := make([]byte, len(expr.Elts))
+		for i, elt := range expr.Elts {
+			b, ok := intValue(elt)
+			if !ok || b < 0 || b > 255 {
+				return nil, false
+			}
+			data[i] = byte(b)
+		}
+		return data, true
+	}
+	return nil, false
+}
+
+// isByteSlice reports whether the expression is the type []byte.
+func isByteSlice(expr ast.Expr) bool {
+	typ, ok := expr.(*ast.ArrayType)
+	if !ok || typ.Len != nil {
+		return false
+	}
+	elt, ok := typ.Elt.(*ast.Ident)
+	return ok && (elt.Name == "byte" || elt.Name == "uint8")
+}
+
+// stringValue evaluates a string literal, possibly made of several
+// concatenated strings. The chain of concatenations is walked with a stack
+// into a single builder, as a wrapped asset has one for each line.
+func stringValue(expr ast.Expr) (string, bool) {
+	var b strings.Builder
+	stack := []ast.Expr{expr}
+	for len(stack) > 0 {
+		expr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch expr := expr.(type) {
+		case *ast.BasicLit:
+			if expr.Kind != token.STRING {
+				return "", false
+			}
+			s, err := strconv.Unquote(expr.Value)
+			if err != nil {
+				return "", false
+			}
+			b.WriteString(s)
+		case *ast.BinaryExpr:
+			if expr.Op != token.ADD {
+				return "", false
+			}
+			stack = append(stack, expr.Y, expr.X)
+		case *ast.ParenExpr:
+			stack = append(stack, expr.X)
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+// intValue evaluates an integer or character literal, possibly negated or
+// converted, as in os.FileMode(420) or time.Unix(1, 0).
+func intValue(expr ast.Expr) (int64, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		switch expr.Kind {
+		case token.INT:
+			i, err := strconv.ParseInt(expr.Value, 0, 64)
+			return i, err == nil
+		case token.CHAR:
+			s, err := strconv.Unquote(expr.Value)
+			if err != nil || len(s) == 0 {
+				return 0, false
+			}
+			return int64([]rune(s)[0]), true
+		}
+	case *ast.UnaryExpr:
+		if expr.Op == token.SUB {
+			i, ok := intValue(expr.X)
+			return -i, ok
+		}
+	case *ast.ParenExpr:
+		return intValue(expr.X)
+	case *ast.CallExpr:
+		if len(expr.Args) > 0 {
+			return intValue(expr.Args[0])
+		}
+	}
+	return 0, false
+}
+
+// literalFields returns the keyed fields of a composite literal.
+func literalFields(lit *ast.CompositeLit) map[string]ast.Expr {
+	fields := make(map[string]ast.Expr)
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				fields[key.Name] = kv.Value
+			}
+		}
+	}
+	return fields
+}
+
+// unaryOperand returns the operand of a unary expression such as &x.
+func unaryOperand(expr ast.Expr) ast.Expr {
+	if unary, ok := expr.(*ast.UnaryExpr); ok {
+		return unary.X
+	}
+	return expr
+}
+
+// indexString returns the source of a simple expression, such as an index.
+func indexString(expr ast.Expr) string {
+	if lit, ok := expr.(*ast.BasicLit); ok {
+		return lit.Value
+	}
+	return "?"
+}
+
+// sliceData returns length bytes of data from offset.
+func sliceData(data []byte, offset, length int64) ([]byte, error) {
+	if offset < 0 || length < 0 || offset+length > int64(len(data)) {
+		return nil, fmt.Errorf("data out of range")
+	}
+	return data[offset : offset+length], nil
+}
+
+// gunzip decompresses gzip data.
+func gunzip(data []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+	return out, gz.Close()
+}
+
+// buildTags returns the tags of the `// +build` line of a file.
+func buildTags(f *ast.File) string {
+	for _, group := range f.Comments {
+		if group.Pos() > f.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, "// +build ") {
+				return strings.TrimSpace(strings.TrimPrefix(comment.Text, "// +build "))
+			}
+		}
+	}
+	return ""
+}
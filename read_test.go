@@ -0,0 +1,277 @@
+package bindata
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestReadGenerated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	for _, sub := range []string{"sub", "empty"} {
+		if err := os.MkdirAll(filepath.Join(in, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		"a.txt":     "text with `quotes`\r\n",
+		"b.bin":     "\x00\x01\x02\xff",
+		"copy.txt":  "text with `quotes`\r\n",
+		"sub/c.txt": "",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(in, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	configs := map[string]func(c *Config){
+		"compressed":         func(c *Config) {},
+		"nomemcopy":          func(c *Config) { c.NoMemCopy = true },
+		"uncompressed":       func(c *Config) { c.NoCompress = true },
+		"uncompressed bytes": func(c *Config) { c.NoCompress = true; c.Encoding = EncodingBytes },
+		"split":              func(c *Config) { c.SplitSize = 1 },
+		"packed":             func(c *Config) { c.Packed = true; c.NoMemCopy = true },
+		"solid":              func(c *Config) { c.Solid = 1024 },
+		"embed":              func(c *Config) { c.Embed = true },
+		"assembly":           func(c *Config) { c.Assembly = true },
+		"archive":            func(c *Config) { c.Archive = filepath.Join(dir, "assets.bin") },
+	}
+	for name, configure := range configs {
+		c := NewConfig()
+		c.Input = []InputConfig{{Path: in, Recursive: true}}
+		c.Prefix = in
+		c.Output = filepath.Join(dir, "bindata.go")
+		c.ModTime = 12345
+		configure(c)
+
+		if err := Translate(c); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		g, err := ReadGenerated(c.Output)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if g.Package != "main" || !g.declares("Asset") || !g.declares("RestoreAssets") {
+			t.Errorf("%s: unexpected package %q or functions %v", name, g.Package, g.Funcs)
+		}
+		if len(g.Assets) != len(files) {
+			t.Fatalf("%s: expected %d assets, got %d", name, len(files), len(g.Assets))
+		}
+		for _, asset := range g.Assets {
+			// Only the info of archived assets is in the generated code.
+			if archived := len(c.Archive) > 0; asset.Archived != archived {
+				t.Errorf("%s: %s: expected archived %v", name, asset.Name, archived)
+			} else if !archived && string(asset.Data) != files[asset.Name] {
+				t.Errorf("%s: %s: expected %q, got %q", name, asset.Name, files[asset.Name], asset.Data)
+			}
+			if asset.Mode != 0600 || asset.ModTime != 12345 || asset.Size != int64(len(files[asset.Name])) {
+				t.Errorf("%s: %s: unexpected info %v %d %d", name, asset.Name, asset.Mode, asset.ModTime, asset.Size)
+			}
+		}
+		if len(g.Dirs) != 2 || g.Dirs[0].Name != "empty" || g.Dirs[1].Name != "sub" {
+			t.Fatalf("%s: expected the directories empty and sub, got %v", name, g.Dirs)
+		}
+		for _, d := range g.Dirs {
+			if d.Mode != os.ModeDir|0755 || d.ModTime != 12345 {
+				t.Errorf("%s: %s: unexpected info %v %d", name, d.Name, d.Mode, d.ModTime)
+			}
+		}
+
+		os.Remove(legacyPath(c.Output))
+	}
+}
+
+func TestReadGeneratedSamples(t *testing.T) {
+	expected, err := ioutil.ReadFile("testdata/in/test.asset")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"compress-memcopy", "compress-nomemcopy", "nocompress-memcopy", "nocompress-nomemcopy"} {
+		g, err := ReadGenerated(filepath.Join("testdata", "out", name+".go"))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(g.Assets) != 4 {
+			t.Fatalf("%s: expected 4 assets, got %d", name, len(g.Assets))
+		}
+		for _, asset := range g.Assets {
+			if string(asset.Data) != string(expected) || asset.ModTime != 1587188643 {
+				t.Errorf("%s: %s: unexpected contents %q or modification time %d", name, asset.Name, asset.Data, asset.ModTime)
+			}
+		}
+	}
+
+	if _, err := ReadGenerated(filepath.Join("testdata", "out", "debug.go")); err == nil {
+		t.Errorf("expected error reading a debug build")
+	}
+}
+
+func TestReadGeneratedLargeLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := make([]byte, 4<<20)
+	rand.New(rand.NewSource(1)).Read(data)
+	in := filepath.Join(dir, "in")
+	writeTestFiles(t, map[string]string{filepath.Join(in, "large.bin"): string(data)})
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in}}
+	c.Prefix = in
+	c.Output = filepath.Join(dir, "bindata.go")
+	c.NoCompress = true
+	c.Encoding = EncodingLines
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Copying the string for each of its lines would allocate terabytes.
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	g, err := ReadGenerated(c.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 256<<20 {
+		t.Errorf("expected at most 256 MB to be allocated, got %d MB", alloc>>20)
+	}
+
+	if len(g.Assets) != 1 || !bytes.Equal(g.Assets[0].Data, data) {
+		t.Errorf("expected the large asset to be read back")
+	}
+}
+
+func TestRestoreGenerated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := &Generated{Assets: []GeneratedAsset{
+		{Name: "a/b.txt", Data: []byte("b"), Mode: 0600, ModTime: 1000},
+		{Name: "/abs.txt", Data: []byte("abs")},
+		{Name: "a/link", Link: "b.txt", Mode: os.ModeSymlink | 0777},
+	}, Dirs: []GeneratedAsset{
+		{Name: "a", Mode: os.ModeDir | 0700, ModTime: 2000},
+		{Name: "empty", Mode: os.ModeDir | 0750, ModTime: 3000},
+	}}
+	if asset := g.Asset("a/link"); asset == nil || asset.Link != "b.txt" {
+		t.Errorf("expected to find a/link, got %v", asset)
+	}
+	if asset := g.Asset("missing"); asset != nil {
+		t.Errorf("expected no asset, got %v", asset)
+	}
+
+	if err := g.Restore(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(filepath.Join(dir, "a", "b.txt"))
+	if err != nil || fi.Mode() != 0600 || fi.ModTime().Unix() != 1000 {
+		t.Errorf("unexpected restored file %v, %v", fi, err)
+	}
+	if fi, err = os.Stat(filepath.Join(dir, "abs.txt")); err != nil || fi.Mode() != 0644 {
+		t.Errorf("unexpected restored file %v, %v", fi, err)
+	}
+	if link, err := os.Readlink(filepath.Join(dir, "a", "link")); err != nil || link != "b.txt" {
+		t.Errorf("unexpected restored link %q, %v", link, err)
+	}
+	if fi, err = os.Stat(filepath.Join(dir, "a")); err != nil || fi.Mode() != os.ModeDir|0700 || fi.ModTime().Unix() != 2000 {
+		t.Errorf("unexpected restored directory %v, %v", fi, err)
+	}
+	if fi, err = os.Stat(filepath.Join(dir, "empty")); err != nil || fi.Mode() != os.ModeDir|0750 || fi.ModTime().Unix() != 3000 {
+		t.Errorf("unexpected restored directory %v, %v", fi, err)
+	}
+	g.Dirs = nil
+
+	g.Assets = []GeneratedAsset{{Name: "../escape"}}
+	if err := g.Restore(dir); err == nil {
+		t.Errorf("expected error restoring an asset outside of the directory")
+	}
+
+	// Links leaving the directory are refused unless allowed, and nothing
+	// is written through a link.
+	outside := filepath.Join(dir, "outside")
+	for _, link := range []string{outside, "../outside", "a/../../outside"} {
+		g.Assets = []GeneratedAsset{{Name: "x", Link: link}}
+		if err := g.Restore(filepath.Join(dir, "out")); err == nil {
+			t.Errorf("%s: expected error restoring a link leaving the directory", link)
+		}
+	}
+	if err := os.Mkdir(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+	g.Assets = []GeneratedAsset{{Name: "x", Link: outside}, {Name: "x/pwned", Data: []byte("x")}}
+	g.OutsideLinks = true
+	if err := g.Restore(filepath.Join(dir, "out")); err == nil {
+		t.Errorf("expected error restoring an asset below a link")
+	}
+	if _, err := os.Lstat(filepath.Join(outside, "pwned")); !os.IsNotExist(err) {
+		t.Errorf("expected no file written through the link, got %v", err)
+	}
+	if link, err := os.Readlink(filepath.Join(dir, "out", "x")); err != nil || link != outside {
+		t.Errorf("unexpected restored link %q, %v", link, err)
+	}
+
+	// Links are resolved against those already restored, and a link
+	// restored later cannot change where an earlier one leads.
+	g.OutsideLinks = false
+	m := GeneratedAsset{Name: "a/b/m", Link: "../.."}
+	l := GeneratedAsset{Name: "a/b/l", Link: "m/../escaped"}
+	for out, assets := range map[string][]GeneratedAsset{"ml": {m, l}, "lm": {l, m}} {
+		g.Assets = assets
+		if err := g.Restore(filepath.Join(dir, out)); err == nil {
+			t.Errorf("%s: expected error restoring a link leaving the directory through another", out)
+		}
+	}
+}
+
+func TestLinkLeavesGenerated(t *testing.T) {
+	fset := token.NewFileSet()
+	lib, err := parser.ParseFile(fset, "read.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen, err := parser.ParseFile(fset, "", "package main\n"+linkLeavesCode, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := func(f *ast.File, name string) string {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == name {
+				var buf bytes.Buffer
+				printer.Fprint(&buf, fset, fn.Type)
+				printer.Fprint(&buf, fset, fn.Body)
+				return buf.String()
+			}
+		}
+		t.Fatalf("%s not found", name)
+		return ""
+	}
+	if lib, gen := source(lib, "linkLeaves"), source(gen, "_linkLeaves"); lib != gen {
+		t.Errorf("expected the generated _linkLeaves to match linkLeaves\n%s\ngot\n%s", lib, gen)
+	}
+}
@@ -6,12 +6,10 @@ package bindata
 
 import (
 	"bytes"
-	"compress/gzip"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
-	"unicode/utf8"
 )
 
 // writeRelease writes the release code file.
@@ -63,7 +61,11 @@ func writeReleaseHeader(w io.Writer, c *Config) error {
 // A release entry is a function which embeds and returns
 // the file's byte content.
 func writeReleaseAsset(w io.Writer, c *Config, asset *Asset) error {
-	fd, err := os.Open(asset.Path)
+	if len(asset.Alias) > 0 {
+		return asset_release_common(w, c, asset)
+	}
+
+	fd, err := openAsset(asset)
 	if err != nil {
 		return err
 	}
@@ -72,15 +74,15 @@ func writeReleaseAsset(w io.Writer, c *Config, asset *Asset) error {
 
 	if c.NoCompress {
 		if c.NoMemCopy {
-			err = uncompressed_nomemcopy(w, asset, fd)
+			err = uncompressed_nomemcopy(w, c, asset, fd)
 		} else {
-			err = uncompressed_memcopy(w, asset, fd)
+			err = uncompressed_memcopy(w, c, asset, fd)
 		}
 	} else {
 		if c.NoMemCopy {
-			err = compressed_nomemcopy(w, asset, fd)
+			err = compressed_nomemcopy(w, c, asset, fd)
 		} else {
-			err = compressed_memcopy(w, asset, fd)
+			err = compressed_memcopy(w, c, asset, fd)
 		}
 	}
 	if err != nil {
@@ -89,17 +91,15 @@ func writeReleaseAsset(w io.Writer, c *Config, asset *Asset) error {
 	return asset_release_common(w, c, asset)
 }
 
-// sanitize prepares a valid UTF-8 string as a raw string constant.
-// Based on https://code.google.com/p/go/source/browse/godoc/static/makestatic.go?repo=tools
-func sanitize(b []byte) []byte {
-	// Replace ` with `+"`"+`
-	b = bytes.Replace(b, []byte("`"), []byte("`+\"`\"+`"), -1)
-
-	// Replace BOM with `+"\xEF\xBB\xBF"+`
-	// (A BOM is valid UTF-8 but not permitted in Go source files.
-	// I wouldn't bother handling this, but for some insane reason
-	// jquery.js has a BOM somewhere in the middle.)
-	return bytes.Replace(b, []byte("\xEF\xBB\xBF"), []byte("`+\"\\xEF\\xBB\\xBF\"+`"), -1)
+// openAsset opens the contents of an asset. Preserved symbolic links which
+// do not point to a regular file have no contents.
+func openAsset(asset *Asset) (io.ReadCloser, error) {
+	if len(asset.Link) > 0 {
+		if fi, err := os.Stat(asset.Path); err != nil || !fi.Mode().IsRegular() {
+			return ioutil.NopCloser(bytes.NewReader(nil)), nil
+		}
+	}
+	return os.Open(asset.Path)
 }
 
 func header_compressed_nomemcopy(w io.Writer, c *Config) error {
@@ -324,6 +324,7 @@ func header_release_common(w io.Writer) error {
 	_, err := fmt.Fprintf(w, `type asset struct {
 	bytes []byte
 	info  os.FileInfo
+	link  string
 }
 
 type bindataFileInfo struct {
@@ -367,21 +368,26 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return err
 }
 
-func compressed_nomemcopy(w io.Writer, asset *Asset, r io.Reader) error {
-	_, err := fmt.Fprintf(w, `var _%s = "`, asset.Func)
+func compressed_nomemcopy(w io.Writer, c *Config, asset *Asset, r io.Reader) error {
+	_, err := fmt.Fprintf(w, `var _%s = `, asset.Func)
 	if err != nil {
 		return err
 	}
 
-	gz := gzip.NewWriter(&StringWriter{Writer: w})
-	_, err = io.Copy(gz, r)
-	gz.Close()
+	dw, end, err := openData(w, c, false, 0)
+	if err != nil {
+		return err
+	}
 
+	err = compress(dw, c, asset, r)
 	if err != nil {
 		return err
 	}
+	if err = end(); err != nil {
+		return err
+	}
 
-	_, err = fmt.Fprintf(w, `"
+	_, err = fmt.Fprintf(w, `
 
 func %sBytes() ([]byte, error) {
 	return bindataRead(
@@ -394,21 +400,26 @@ func %sBytes() ([]byte, error) {
 	return err
 }
 
-func compressed_memcopy(w io.Writer, asset *Asset, r io.Reader) error {
-	_, err := fmt.Fprintf(w, `var _%s = []byte("`, asset.Func)
+func compressed_memcopy(w io.Writer, c *Config, asset *Asset, r io.Reader) error {
+	_, err := fmt.Fprintf(w, `var _%s = `, asset.Func)
 	if err != nil {
 		return err
 	}
 
-	gz := gzip.NewWriter(&StringWriter{Writer: w})
-	_, err = io.Copy(gz, r)
-	gz.Close()
+	dw, end, err := openData(w, c, true, 0)
+	if err != nil {
+		return err
+	}
 
+	err = compress(dw, c, asset, r)
 	if err != nil {
 		return err
 	}
+	if err = end(); err != nil {
+		return err
+	}
 
-	_, err = fmt.Fprintf(w, `")
+	_, err = fmt.Fprintf(w, `
 
 func %sBytes() ([]byte, error) {
 	return bindataRead(
@@ -421,18 +432,18 @@ func %sBytes() ([]byte, error) {
 	return err
 }
 
-func uncompressed_nomemcopy(w io.Writer, asset *Asset, r io.Reader) error {
-	_, err := fmt.Fprintf(w, `var _%s = "`, asset.Func)
+func uncompressed_nomemcopy(w io.Writer, c *Config, asset *Asset, r io.Reader) error {
+	_, err := fmt.Fprintf(w, `var _%s = `, asset.Func)
 	if err != nil {
 		return err
 	}
 
-	_, err = io.Copy(&StringWriter{Writer: w}, r)
+	err = writeLiteral(w, c, asset, r, false, 0)
 	if err != nil {
 		return err
 	}
 
-	_, err = fmt.Fprintf(w, `"
+	_, err = fmt.Fprintf(w, `
 
 func %sBytes() ([]byte, error) {
 	return bindataRead(
@@ -445,23 +456,18 @@ func %sBytes() ([]byte, error) {
 	return err
 }
 
-func uncompressed_memcopy(w io.Writer, asset *Asset, r io.Reader) error {
-	_, err := fmt.Fprintf(w, `var _%s = []byte(`, asset.Func)
+func uncompressed_memcopy(w io.Writer, c *Config, asset *Asset, r io.Reader) error {
+	_, err := fmt.Fprintf(w, `var _%s = `, asset.Func)
 	if err != nil {
 		return err
 	}
 
-	b, err := ioutil.ReadAll(r)
+	err = writeLiteral(w, c, asset, r, true, 0)
 	if err != nil {
 		return err
 	}
-	if utf8.Valid(b) && !bytes.Contains(b, []byte{0}) {
-		fmt.Fprintf(w, "`%s`", sanitize(b))
-	} else {
-		fmt.Fprintf(w, "%+q", b)
-	}
 
-	_, err = fmt.Fprintf(w, `)
+	_, err = fmt.Fprintf(w, `
 
 func %sBytes() ([]byte, error) {
 	return _%s, nil
@@ -471,26 +477,116 @@ func %sBytes() ([]byte, error) {
 	return err
 }
 
+// openData writes the opening of a literal for embedded data in the
+// configured encoding, a byte slice if slice is set and a string otherwise.
+// Depth is the number of parenthesized groups the literal is nested in. It
+// returns the writer encoding the data and a function writing the end
+// of the literal.
+func openData(w io.Writer, c *Config, slice bool, depth int) (io.Writer, func() error, error) {
+	var opening, closing string
+	var dw io.Writer
+	var sw *StringWriter
+
+	switch {
+	case c.Encoding == EncodingBytes && slice:
+		opening, closing = "[]byte{", "\n}"
+		dw = &ByteWriter{Writer: w, Width: c.width()}
+	case slice:
+		opening, closing = `[]byte("`, ")"
+		sw = &StringWriter{Writer: w, Width: c.width(), Depth: depth}
+		dw = sw
+	default:
+		opening = `"`
+		sw = &StringWriter{Writer: w, Width: c.width(), Depth: depth}
+		dw = sw
+	}
+
+	end := func() error {
+		if sw != nil {
+			if _, err := fmt.Fprint(w, `"`); err != nil {
+				return err
+			}
+			if err := sw.Close(); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprint(w, closing)
+		return err
+	}
+
+	_, err := fmt.Fprint(w, opening)
+	return dw, end, err
+}
+
+// writeLiteral streams the contents of the asset from r as a literal,
+// a byte slice if slice is set and a string otherwise. Unless the bytes
+// encoding is used, text is written as a raw string, if it has no more
+// than rawSplices characters to splice in. Whether the asset is such
+// text is determined by reading it once up front. Depth is passed on to
+// openData.
+func writeLiteral(w io.Writer, c *Config, asset *Asset, r io.Reader, slice bool, depth int) error {
+	text := false
+	if c.Encoding != EncodingBytes || !slice {
+		fd, err := openAsset(asset)
+		if err != nil {
+			return err
+		}
+
+		var splices int
+		text, splices, err = scanText(fd)
+		fd.Close()
+		if err != nil {
+			return err
+		}
+		text = text && splices <= rawSplices
+	}
+
+	if !text {
+		dw, end, err := openData(w, c, slice, depth)
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(dw, r); err != nil {
+			return err
+		}
+		return end()
+	}
+
+	opening, closing := "`", "`"
+	if slice {
+		opening, closing = "[]byte(`", "`)"
+	}
+
+	if _, err := fmt.Fprint(w, opening); err != nil {
+		return err
+	}
+	rw := &RawStringWriter{Writer: w}
+	if _, err := io.Copy(rw, r); err != nil {
+		return err
+	}
+	if err := rw.Close(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprint(w, closing)
+	return err
+}
+
 func asset_release_common(w io.Writer, c *Config, asset *Asset) error {
-	fi, err := os.Stat(asset.Path)
+	size, mode, modTime, err := asset.metadata(c)
 	if err != nil {
 		return err
 	}
 
-	mode := uint(fi.Mode())
-	modTime := fi.ModTime().Unix()
-	size := fi.Size()
-	if c.NoMetadata {
-		mode = 0
-		modTime = 0
-		size = 0
+	bytesFunc := asset.Func
+	if len(asset.Alias) > 0 {
+		bytesFunc = asset.Alias
 	}
-	if c.Mode > 0 {
-		mode = uint(os.ModePerm) & c.Mode
-	}
-	if c.ModTime > 0 {
-		modTime = c.ModTime
+
+	link := ""
+	if len(asset.Link) > 0 {
+		link = fmt.Sprintf(", link: %q", asset.Link)
 	}
+
 	_, err = fmt.Fprintf(w, `func %s() (*asset, error) {
 	bytes, err := %sBytes()
 	if err != nil {
@@ -498,10 +594,10 @@ func asset_release_common(w io.Writer, c *Config, asset *Asset) error {
 	}
 
 	info := bindataFileInfo{name: %q, size: %d, mode: os.FileMode(%d), modTime: time.Unix(%d, 0)}
-	a := &asset{bytes: bytes, info: info}
+	a := &asset{bytes: bytes, info: info%s}
 	return a, nil
 }
 
-`, asset.Func, asset.Func, asset.Name, size, mode, modTime)
+`, asset.Func, bytesFunc, asset.Name, size, mode, modTime, link)
 	return err
 }
@@ -9,9 +9,19 @@ import (
 	"io"
 )
 
-func writeRestore(w io.Writer) error {
+func writeRestore(w io.Writer, c *Config) error {
+	load := `a, err := _bindata[strings.Replace(name, "\\", "/", -1)]()`
+	if c.layered() {
+		load = `f, _ := bindataLookup(strings.Replace(name, "\\", "/", -1))
+	a, err := f()`
+	} else if c.dynamic() {
+		load = `f, _ := bindataDisk(strings.Replace(name, "\\", "/", -1))
+	a, err := f()`
+	}
+
 	_, err := fmt.Fprintf(w, `
-// RestoreAsset restores an asset under the given directory
+// RestoreAsset restores an asset under the given directory. Symbolic links
+// below the directory are never written through.
 func RestoreAsset(dir, name string) error {
 	data, err := Asset(name)
 	if err != nil {
@@ -21,14 +31,31 @@ func RestoreAsset(dir, name string) error {
 	if err != nil {
 		return err
 	}
-	err = os.MkdirAll(_filePath(dir, filepath.Dir(name)), os.FileMode(0755))
+	err = _mkdirNoLinks(dir, filepath.Dir(_filePath("", name)))
 	if err != nil {
 		return err
 	}
+	// An existing link is replaced rather than written through.
+	if fi, err := os.Lstat(_filePath(dir, name)); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		err = os.Remove(_filePath(dir, name))
+		if err != nil {
+			return err
+		}
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return _restoreLink(dir, name)
+	}
 	err = ioutil.WriteFile(_filePath(dir, name), data, info.Mode())
 	if err != nil {
 		return err
 	}
+	if info.Mode().Perm() != 0 {
+		// WriteFile leaves the mode of existing files and applies the umask.
+		err = os.Chmod(_filePath(dir, name), info.Mode())
+		if err != nil {
+			return err
+		}
+	}
 	err = os.Chtimes(_filePath(dir, name), info.ModTime(), info.ModTime())
 	if err != nil {
 		return err
@@ -44,12 +71,84 @@ func RestoreAssets(dir, name string) error {
 		return RestoreAsset(dir, name)
 	}
 	// Dir
+	err = _mkdirNoLinks(dir, _filePath("", name))
+	if err != nil {
+		return err
+	}
 	for _, child := range children {
 		err = RestoreAssets(dir, filepath.Join(name, child))
 		if err != nil {
 			return err
 		}
 	}
+	return _restoreDirInfo(dir, name)
+}
+
+// _restoreDirInfo applies the recorded mode and modification time
+// to a restored directory, once its children have been written.
+func _restoreDirInfo(dir, name string) error {
+	info, ok := _bindataDirs[strings.Replace(name, "\\", "/", -1)]
+	if !ok {
+		return nil
+	}
+	if info.Mode().Perm() != 0 {
+		err := os.Chmod(_filePath(dir, name), info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+	}
+	return os.Chtimes(_filePath(dir, name), info.ModTime(), info.ModTime())
+}
+
+// _restoreLink recreates a preserved symbolic link under the given
+// directory, unless its target lies outside of the directory.
+func _restoreLink(dir, name string) error {
+	%s
+	if err != nil {
+		return err
+	}
+	if _linkLeaves(dir, strings.Replace(name, "\\", "/", -1), a.link) {
+		return fmt.Errorf("cannot restore %%s: link to %%s leaves the directory", name, a.link)
+	}
+	err = os.Remove(_filePath(dir, name))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Symlink(a.link, _filePath(dir, name))
+}
+
+// _mkdirNoLinks creates the directory rel below dir, failing if any part
+// of it is a symbolic link or not a directory, or leaves dir.
+func _mkdirNoLinks(dir, rel string) error {
+	err := os.MkdirAll(dir, os.FileMode(0755))
+	if err != nil {
+		return err
+	}
+	if rel == "" || rel == "." {
+		return nil
+	}
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("cannot restore to %%s: invalid name", rel)
+	}
+
+	p := dir
+	for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+		p = filepath.Join(p, elem)
+		fi, err := os.Lstat(p)
+		switch {
+		case os.IsNotExist(err):
+			err = os.Mkdir(p, os.FileMode(0755))
+			if err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case fi.Mode()&os.ModeSymlink != 0:
+			return fmt.Errorf("%%s is a symbolic link", p)
+		case !fi.IsDir():
+			return fmt.Errorf("%%s is not a directory", p)
+		}
+	}
 	return nil
 }
 
@@ -57,6 +156,55 @@ func _filePath(dir, name string) string {
 	cannonicalName := strings.Replace(name, "\\", "/", -1)
 	return filepath.Join(append([]string{dir}, strings.Split(cannonicalName, "/")...)...)
 }
-`)
+`, load)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, linkLeavesCode)
 	return err
 }
+
+// linkLeavesCode is the generated counterpart of linkLeaves, which
+// Generated.Restore uses.
+const linkLeavesCode = `
+// _linkLeaves reports whether the target of the link with the given slash
+// separated name is absolute or leads outside of dir. The target is
+// resolved against what has been restored so far, so that links already
+// in dir are followed.
+func _linkLeaves(dir, name, target string) bool {
+	target = filepath.ToSlash(target)
+	if filepath.IsAbs(target) || strings.HasPrefix(target, "/") {
+		return true
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return true
+	}
+	p, err := filepath.EvalSymlinks(filepath.Join(dir, filepath.Dir(filepath.FromSlash(name))))
+	if err != nil {
+		return true
+	}
+	exists := true
+	for _, elem := range strings.Split(target, "/") {
+		switch elem {
+		case "", ".":
+		case "..":
+			// Where a directory not restored yet leads back to is unknown,
+			// it may still become a link.
+			if !exists {
+				return true
+			}
+			p = filepath.Dir(p)
+		default:
+			p = filepath.Join(p, elem)
+			if real, err := filepath.EvalSymlinks(p); err == nil {
+				p = real
+			} else {
+				exists = false
+			}
+		}
+	}
+	rel, err := filepath.Rel(root, p)
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+`
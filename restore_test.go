@@ -0,0 +1,119 @@
+package bindata
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const restoreMain = `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	os.MkdirAll("../out", 0755)
+	os.Symlink("../outside", "../out/sub")
+	fmt.Println(RestoreAsset("../out", "inside"))
+	link, err := os.Readlink("../out/inside")
+	fmt.Println(link, err)
+	fmt.Println(RestoreAsset("../out", "leave") != nil)
+	fmt.Println(RestoreAsset("../out", "sub/f.txt") != nil)
+	fmt.Println(RestoreAssets("../out", "sub") != nil)
+}
+`
+
+func TestRestoreAssetLinks(t *testing.T) {
+	dir, mod := newTestModule(t, restoreMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	writeTestFiles(t, map[string]string{
+		filepath.Join(in, "a.txt"):        "a\n",
+		filepath.Join(in, "sub", "f.txt"): "f\n",
+	})
+	if err := os.Mkdir(filepath.Join(dir, "outside"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, target := range map[string]string{"inside": "a.txt", "leave": "../outside"} {
+		if err := os.Symlink(target, filepath.Join(in, name)); err != nil {
+			t.Skip(err)
+		}
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true, Symlinks: SymlinkPreserve}}
+	c.Prefix = in
+	c.Output = filepath.Join(mod, "bindata.go")
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Neither links leaving the directory are restored, nor is anything
+	// written through a link found in it.
+	out, err := goCommand(t, mod, nil, "run", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	expected := "<nil>\na.txt <nil>\ntrue\ntrue\ntrue\n"
+	if string(out) != expected {
+		t.Errorf("expected output\n%s\ngot\n%s", expected, out)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "out", "leave")); !os.IsNotExist(err) {
+		t.Errorf("expected no link leaving the directory, got %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "outside", "f.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected no file written through a link, got %v", err)
+	}
+}
+
+const restoreThroughLinksMain = `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Println(RestoreAsset("../out", "a/b/m"))
+	fmt.Println(RestoreAsset("../out", "a/b/l") != nil)
+	os.RemoveAll("../out")
+	fmt.Println(RestoreAsset("../out", "a/b/l") != nil)
+}
+`
+
+func TestRestoreAssetThroughLinks(t *testing.T) {
+	dir, mod := newTestModule(t, restoreThroughLinksMain)
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	if err := os.MkdirAll(filepath.Join(in, "a", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, target := range map[string]string{"m": "../..", "l": "m/../escaped"} {
+		if err := os.Symlink(target, filepath.Join(in, "a", "b", name)); err != nil {
+			t.Skip(err)
+		}
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true, Symlinks: SymlinkPreserve}}
+	c.Prefix = in
+	c.Output = filepath.Join(mod, "bindata.go")
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lexically m/../escaped stays in a/b, but m already leads to the top
+	// of the directory, and the link must not be restored before m is.
+	out, err := goCommand(t, mod, nil, "run", ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	expected := "<nil>\ntrue\ntrue\n"
+	if string(out) != expected {
+		t.Errorf("expected output\n%s\ngot\n%s", expected, out)
+	}
+}
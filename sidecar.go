@@ -0,0 +1,244 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// sidecarSuffix is appended to the name of a file to form the name of
+	// its sidecar file, e.g. foo.css.meta.json.
+	sidecarSuffix = ".meta.json"
+
+	// dirMetaFile is the name of the per-directory metadata rule file.
+	dirMetaFile = ".bindatameta.json"
+)
+
+// sidecar holds the metadata read from a sidecar file, or from one rule of
+// a directory rule file.
+type sidecar struct {
+	Pattern string            `json:"pattern"`
+	Meta    map[string]string `json:"meta"`
+	Tags    []string          `json:"tags"`
+
+	re *regexp.Regexp
+}
+
+// dirMeta holds the rules of a directory rule file.
+type dirMeta struct {
+	dir   string
+	rules []sidecar
+}
+
+// applySidecars attaches the metadata of sidecar files and directory rule
+// files to the assets in the table of contents. Rule files apply to the
+// files in their directory and below, outer directories first. A sidecar
+// file is applied last. The sidecar and rule files are removed from the
+// returned table of contents.
+func applySidecars(toc []Asset) ([]Asset, error) {
+	var dirs []dirMeta
+	var sidecars = make(map[string]*sidecar)
+	var assets []Asset
+
+	for _, asset := range toc {
+		switch {
+		case filepath.Base(asset.Path) == dirMetaFile:
+			var rules []sidecar
+			if err := readSidecar(asset.Path, &rules); err != nil {
+				return nil, err
+			}
+			for i := range rules {
+				pattern := rules[i].Pattern
+				if len(pattern) == 0 {
+					pattern = "**"
+				}
+				re, err := compileGlob(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("%s: invalid pattern %q: %v", asset.Path, pattern, err)
+				}
+				rules[i].re = re
+			}
+			dirs = append(dirs, dirMeta{dir: filepath.Dir(asset.Path), rules: rules})
+		case strings.HasSuffix(asset.Path, sidecarSuffix) && isFile(strings.TrimSuffix(asset.Path, sidecarSuffix)):
+			var meta sidecar
+			if err := readSidecar(asset.Path, &meta); err != nil {
+				return nil, err
+			}
+			sidecars[strings.TrimSuffix(asset.Path, sidecarSuffix)] = &meta
+		default:
+			assets = append(assets, asset)
+		}
+	}
+
+	// Outer directories come first, so inner rules take precedence.
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i].dir, string(filepath.Separator)) < strings.Count(dirs[j].dir, string(filepath.Separator))
+	})
+
+	for i := range assets {
+		asset := &assets[i]
+		for _, d := range dirs {
+			rel, err := filepath.Rel(d.dir, asset.Path)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			for j := range d.rules {
+				if d.rules[j].re.MatchString(filepath.ToSlash(rel)) {
+					asset.addMeta(&d.rules[j])
+				}
+			}
+		}
+		if meta, ok := sidecars[asset.Path]; ok {
+			asset.addMeta(meta)
+		}
+	}
+
+	return assets, nil
+}
+
+// addMeta merges metadata into the asset. Later values of a key replace
+// earlier ones, tags are accumulated.
+func (asset *Asset) addMeta(meta *sidecar) {
+	for k, v := range meta.Meta {
+		if asset.Meta == nil {
+			asset.Meta = make(map[string]string)
+		}
+		asset.Meta[k] = v
+	}
+
+	for _, tag := range meta.Tags {
+		found := false
+		for _, t := range asset.Tags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			asset.Tags = append(asset.Tags, tag)
+		}
+	}
+}
+
+// readSidecar decodes a sidecar or directory rule file.
+func readSidecar(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	return nil
+}
+
+// isFile reports whether path exists and is not a directory.
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
+
+// writeAssetMeta writes the AssetMeta and AssetsWithTag functions along
+// with the tables of metadata and tags they serve.
+func writeAssetMeta(w io.Writer, c *Config, toc []Asset) error {
+	if !c.AssetMeta {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(w, `// AssetMeta returns the metadata attached to the asset with the given
+// name by sidecar files. It returns an error if the asset could not be found.
+func AssetMeta(name string) (map[string]string, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	if _, ok := _bindata[cannonicalName]; !ok {
+		return nil, fmt.Errorf("AssetMeta %%s not found", name)
+	}
+	meta := make(map[string]string, len(_bindataMeta[cannonicalName]))
+	for k, v := range _bindataMeta[cannonicalName] {
+		meta[k] = v
+	}
+	return meta, nil
+}
+
+// AssetsWithTag returns the names of the assets carrying the given tag.
+func AssetsWithTag(tag string) []string {
+	names := make([]string, len(_bindataTags[tag]))
+	copy(names, _bindataTags[tag])
+	return names
+}
+
+// _bindataMeta is a table, holding the metadata of each asset, mapped to its name.
+var _bindataMeta = map[string]map[string]string{
+`)
+	if err != nil {
+		return err
+	}
+
+	tags := make(map[string][]string)
+	for i := range toc {
+		for _, tag := range toc[i].Tags {
+			tags[tag] = append(tags[tag], toc[i].Name)
+		}
+
+		if len(toc[i].Meta) == 0 {
+			continue
+		}
+
+		keys := make([]string, 0, len(toc[i].Meta))
+		for k := range toc[i].Meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, len(keys))
+		for j, k := range keys {
+			pairs[j] = fmt.Sprintf("%q: %q", k, toc[i].Meta[k])
+		}
+
+		_, err = fmt.Fprintf(w, "\t%q: {%s},\n", toc[i].Name, strings.Join(pairs, ", "))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, `}
+
+// _bindataTags is a table, holding the names of the assets carrying each tag.
+var _bindataTags = map[string][]string{
+`)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(tags))
+	for tag := range tags {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+
+	for _, tag := range names {
+		quoted := make([]string, len(tags[tag]))
+		for i, name := range tags[tag] {
+			quoted[i] = fmt.Sprintf("%q", name)
+		}
+
+		_, err = fmt.Fprintf(w, "\t%q: {%s},\n", tag, strings.Join(quoted, ", "))
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeTOCFooter(w)
+}
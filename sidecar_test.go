@@ -0,0 +1,62 @@
+package bindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestApplySidecars(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"logo.png":                    "",
+		"logo.png.meta.json":          `{"meta": {"owner": "web", "cache": "1h"}, "tags": ["hero"]}`,
+		".bindatameta.json":           `[{"pattern": "*.css", "meta": {"cache": "1d"}, "tags": ["style"]}]`,
+		"css/app.css":                 "",
+		"css/print/.bindatameta.json": `[{"meta": {"cache": "none"}, "tags": ["print", "style"]}]`,
+		"css/print/page.css":          "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var toc []Asset
+	input := InputConfig{Path: dir, Prefix: dir, Recursive: true}
+	err = findFiles(dir, input, &toc, nil, []*regexp.Regexp{}, nil, make(map[string]int), make(map[string]bool))
+	if err != nil {
+		t.Fatalf("expected to be no error: %+v", err)
+	}
+
+	toc, err = applySidecars(toc)
+	if err != nil {
+		t.Fatalf("expected to be no error: %+v", err)
+	}
+
+	expected := []Asset{
+		{Name: "css/app.css", Meta: map[string]string{"cache": "1d"}, Tags: []string{"style"}},
+		{Name: "css/print/page.css", Meta: map[string]string{"cache": "none"}, Tags: []string{"style", "print"}},
+		{Name: "logo.png", Meta: map[string]string{"owner": "web", "cache": "1h"}, Tags: []string{"hero"}},
+	}
+	if len(toc) != len(expected) {
+		t.Fatalf("Expected %d assets, got %d: %v", len(expected), len(toc), toc)
+	}
+	for i := range expected {
+		if toc[i].Name != expected[i].Name || !reflect.DeepEqual(toc[i].Meta, expected[i].Meta) || !reflect.DeepEqual(toc[i].Tags, expected[i].Tags) {
+			t.Errorf("Expected %s with %v %v, got %s with %v %v", expected[i].Name, expected[i].Meta, expected[i].Tags, toc[i].Name, toc[i].Meta, toc[i].Tags)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// shardHeader starts every shard file. Only files starting with it are
+// removed as stale shards.
+const shardHeader = "// Code generated by go-bindata."
+
+// shard is a data file holding the embedded contents of some assets.
+type shard struct {
+	path   string
+	assets []Asset
+}
+
+// shardPath returns the path of the n-th shard of the given output file,
+// counting from zero: bindata.go has the shards bindata_data1.go,
+// bindata_data2.go... A bare number would be taken for an architecture by
+// the go tool, as in bindata_386.go.
+func shardPath(output string, n int) string {
+	return fmt.Sprintf("%s_data%d.go", strings.TrimSuffix(output, ".go"), n+1)
+}
+
+// splitAssets distributes the assets over shards as configured by
+// SplitSize and SplitDirs. It returns no shards for debug builds or when
+// splitting is disabled.
+func splitAssets(c *Config, toc []Asset) ([]shard, error) {
+	if c.Debug || c.Dev || (c.SplitSize <= 0 && !c.SplitDirs) {
+		return nil, nil
+	}
+
+	var groups = make(map[string][]Asset)
+	var keys []string
+	for _, asset := range toc {
+		key := ""
+		if c.SplitDirs {
+			if i := strings.Index(asset.Name, "/"); i >= 0 {
+				key = asset.Name[:i]
+			}
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], asset)
+	}
+	sort.Strings(keys)
+
+	var shards []shard
+	for _, key := range keys {
+		var assets []Asset
+		var size int64
+		for _, asset := range groups[key] {
+			n, err := asset.dataSize()
+			if err != nil {
+				return nil, err
+			}
+			if c.SplitSize > 0 && len(assets) > 0 && size+n > c.SplitSize {
+				shards = append(shards, shard{path: shardPath(c.Output, len(shards)), assets: assets})
+				assets, size = nil, 0
+			}
+			assets = append(assets, asset)
+			size += n
+		}
+		shards = append(shards, shard{path: shardPath(c.Output, len(shards)), assets: assets})
+	}
+
+	return shards, nil
+}
+
+// dataSize returns the number of bytes the asset embeds.
+func (asset *Asset) dataSize() (int64, error) {
+	if len(asset.Alias) > 0 {
+		return 0, nil
+	}
+
+	fi, err := os.Stat(asset.Path)
+	if err != nil {
+		if len(asset.Link) > 0 {
+			// A dangling preserved link.
+			return 0, nil
+		}
+		return 0, err
+	}
+	if !fi.Mode().IsRegular() {
+		return 0, nil
+	}
+
+	return fi.Size(), nil
+}
+
+// writeShard writes a shard file with the release entries of the assets,
+// or the n-th chunk of packed data in packed mode. The functions they use
+// are declared in the main output file.
+func writeShard(w io.Writer, c *Config, toc []Asset, pack *packer, n int) error {
+	err := writeHeader(w, c, toc, "")
+	if err != nil {
+		return err
+	}
+
+	if pack != nil {
+		return pack.writeChunk(w, c, toc, n)
+	}
+
+	_, err = fmt.Fprintf(w, `import (
+	"os"
+	"time"
+)
+
+`)
+	if err != nil {
+		return err
+	}
+
+	for i := range toc {
+		err = writeReleaseAsset(w, c, &toc[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeStaleShards removes shard files of the output left over from
+// previous runs, which are not among the given files. Files which were not
+// generated by go-bindata are left alone.
+func removeStaleShards(output string, files []string) error {
+	dir, base := filepath.Split(output)
+	if dir == "" {
+		dir = "."
+	}
+
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(strings.TrimSuffix(base, ".go")) + `_data[0-9]+\.go$`)
+
+	keep := make(map[string]bool)
+	for _, file := range files {
+		keep[filepath.Base(file)] = true
+	}
+
+	list, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, fi := range list {
+		if !fi.Mode().IsRegular() || !pattern.MatchString(fi.Name()) || keep[fi.Name()] {
+			continue
+		}
+
+		path := filepath.Join(dir, fi.Name())
+		if !isShard(path) {
+			continue
+		}
+		if err = os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isShard reports whether the file at path starts with the shard header.
+func isShard(path string) bool {
+	line, err := firstLine(path)
+	return err == nil && strings.HasPrefix(line, shardHeader)
+}
+
+// firstLine returns the first line of the file at path.
+func firstLine(path string) (string, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer fd.Close()
+
+	line, err := bufio.NewReader(fd).ReadString('\n')
+	if err == io.EOF {
+		err = nil
+	}
+	return line, err
+}
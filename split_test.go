@@ -0,0 +1,126 @@
+package bindata
+
+import (
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestShardPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if path := shardPath("assets/bindata.go", 0); path != "assets/bindata_data1.go" {
+		t.Errorf("expected assets/bindata_data1.go, got %s", path)
+	}
+
+	// No shard is taken for a file of another system or architecture.
+	ctx := build.Default
+	ctx.GOOS, ctx.GOARCH = "linux", "amd64"
+	for _, n := range []int{0, 385, 999} {
+		path := shardPath(filepath.Join(dir, "bindata.go"), n)
+		if err := ioutil.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := ctx.MatchFile(dir, filepath.Base(path)); !ok || err != nil {
+			t.Errorf("%s: expected the shard to be built, got %v, %v", path, ok, err)
+		}
+	}
+}
+
+func TestTranslateWithSplit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	files := map[string]string{
+		"index.html":  "index.html",
+		"css/a.css":   "css/a.css ",
+		"css/b.css":   "css/b.css ",
+		"css/c.css":   "css/c.css ",
+		"img/logo.sv": "img/logo.s",
+	}
+	for name, content := range files {
+		path := filepath.Join(in, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := filepath.Join(dir, "out")
+	if err := os.Mkdir(out, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Not generated by go-bindata, must be kept.
+	if err := ioutil.WriteFile(filepath.Join(out, "bindata_data9.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in, Recursive: true}}
+	c.Prefix = in
+	c.Output = filepath.Join(out, "bindata.go")
+
+	tests := []struct {
+		size   int64
+		dirs   bool
+		shards []string
+	}{
+		{25, false, []string{"css/a.css css/b.css", "css/c.css img/logo.sv", "index.html"}},
+		{0, true, []string{"index.html", "css/a.css css/b.css css/c.css", "img/logo.sv"}},
+		{20, true, []string{"index.html", "css/a.css css/b.css", "css/c.css", "img/logo.sv"}},
+		{0, false, nil},
+	}
+
+	for _, test := range tests {
+		c.SplitSize = test.size
+		c.SplitDirs = test.dirs
+		if err := Translate(c); err != nil {
+			t.Fatal(err)
+		}
+
+		var shards []string
+		for i := range test.shards {
+			data, err := ioutil.ReadFile(shardPath(c.Output, i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, line := range strings.Split(string(data), "\n") {
+				if i := strings.LastIndex(line, "/in/"); strings.HasPrefix(line, "// ") && i >= 0 {
+					names = append(names, line[i+len("/in/"):])
+				}
+			}
+			shards = append(shards, strings.Join(names, " "))
+		}
+		if !reflect.DeepEqual(shards, test.shards) {
+			t.Errorf("split %d %v: expected %q, got %q", test.size, test.dirs, test.shards, shards)
+		}
+
+		list, _ := filepath.Glob(filepath.Join(out, "*.go"))
+		sort.Strings(list)
+		expected := []string{c.Output}
+		for i := range test.shards {
+			expected = append(expected, shardPath(c.Output, i))
+		}
+		expected = append(expected, filepath.Join(out, "bindata_data9.go"))
+		sort.Strings(expected)
+		if !reflect.DeepEqual(list, expected) {
+			t.Errorf("split %d %v: expected files %v, got %v", test.size, test.dirs, expected, list)
+		}
+	}
+}
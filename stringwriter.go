@@ -5,14 +5,37 @@
 package bindata
 
 import (
+	"bufio"
 	"io"
+	"unicode/utf8"
 )
 
 const lowerHex = "0123456789abcdef"
 
+// stringGroup is the number of wrapped lines a StringWriter puts in each
+// parenthesized group. go/printer takes quadratic time on a long chain of
+// + operators, which grouping keeps short.
+const stringGroup = 64
+
+// StringWriter writes data as the contents of an interpreted string
+// literal. Printable ASCII is written as is, quotes, backslashes and
+// common control characters use their short escapes and all other
+// bytes are written as \xNN.
+//
+// If Width is set, the literal is wrapped into several literals on
+// separate lines, concatenated with +. A line ends after Width bytes of
+// data, or after a newline in the data. Lines past the first stringGroup
+// are concatenated in parenthesized groups, the last of which Close ends.
+// Lines are indented the way gofmt indents them, by one tab and another
+// for each group they are in. Depth is the number of groups the literal
+// itself is nested in.
 type StringWriter struct {
 	io.Writer
-	c int
+	Width int
+	Depth int
+	c     int
+	line  int
+	lines int
 }
 
 func (w *StringWriter) Write(p []byte) (n int, err error) {
@@ -20,17 +43,184 @@ func (w *StringWriter) Write(p []byte) (n int, err error) {
 		return
 	}
 
-	buf := []byte(`\x00`)
-	var b byte
-
-	for n, b = range p {
-		buf[2] = lowerHex[b/16]
-		buf[3] = lowerHex[b%16]
-		w.Writer.Write(buf)
+	buf := make([]byte, 0, 2*len(p))
+	for _, b := range p {
+		if w.Width > 0 && w.c > 0 && w.line == 0 {
+			w.lines++
+			switch {
+			case w.lines < stringGroup:
+				buf = append(buf, "\" +\n"...)
+				buf = indent(buf, w.Depth+1)
+			case w.lines%stringGroup != 0:
+				buf = append(buf, "\" +\n"...)
+				buf = indent(buf, w.Depth+2)
+			case w.lines == stringGroup:
+				buf = append(buf, "\" +\n"...)
+				buf = append(indent(buf, w.Depth+1), '(')
+			default:
+				buf = append(buf, "\") +\n"...)
+				buf = append(indent(buf, w.Depth+1), '(')
+			}
+			buf = append(buf, '"')
+		}
 		w.c++
+		w.line++
+		if w.line >= w.Width || b == '\n' {
+			w.line = 0
+		}
+
+		switch {
+		case b == '"' || b == '\\':
+			buf = append(buf, '\\', b)
+		case b == '\n':
+			buf = append(buf, '\\', 'n')
+		case b == '\r':
+			buf = append(buf, '\\', 'r')
+		case b == '\t':
+			buf = append(buf, '\\', 't')
+		case b == '\a':
+			buf = append(buf, '\\', 'a')
+		case b == '\b':
+			buf = append(buf, '\\', 'b')
+		case b == '\f':
+			buf = append(buf, '\\', 'f')
+		case b == '\v':
+			buf = append(buf, '\\', 'v')
+		case b >= 0x20 && b < 0x7f:
+			buf = append(buf, b)
+		default:
+			buf = append(buf, '\\', 'x', lowerHex[b/16], lowerHex[b%16])
+		}
+	}
+
+	if _, err = w.Writer.Write(buf); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// indent appends depth tabs to buf.
+func indent(buf []byte, depth int) []byte {
+	for i := 0; i < depth; i++ {
+		buf = append(buf, '\t')
+	}
+	return buf
+}
+
+// Close writes the parenthesis ending the last group of lines, if any.
+// It must be called after the quote closing the literal.
+func (w *StringWriter) Close() error {
+	if w.lines < stringGroup {
+		return nil
+	}
+	_, err := w.Writer.Write([]byte(")"))
+	return err
+}
+
+// RawStringWriter writes valid UTF-8 text as the contents of a raw string
+// literal, which is opened and closed by the caller. Characters a raw
+// string cannot hold, backquotes, carriage returns, NUL and byte order
+// marks, are spliced in as interpreted strings, spaced the way gofmt
+// spaces them. Close must be called to write a rune left incomplete by the
+// last Write.
+type RawStringWriter struct {
+	io.Writer
+	c    int
+	tail []byte
+}
+
+func (w *RawStringWriter) Write(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return
+	}
+
+	data := p
+	if len(w.tail) > 0 {
+		data = append(w.tail, p...)
+		w.tail = nil
+	}
+
+	buf := make([]byte, 0, len(data)+16)
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size <= 1 && !utf8.FullRune(data[i:]) {
+			// Wait for the rest of the rune.
+			w.tail = append([]byte(nil), data[i:]...)
+			break
+		}
+
+		switch r {
+		case '`':
+			buf = append(buf, "` + \"`\" + `"...)
+		case '\r':
+			buf = append(buf, "` + \"\\r\" + `"...)
+		case 0:
+			buf = append(buf, "` + \"\\x00\" + `"...)
+		case '\uFEFF':
+			buf = append(buf, "` + \"\\xef\\xbb\\xbf\" + `"...)
+		default:
+			buf = append(buf, data[i:i+size]...)
+		}
+		i += size
 	}
 
-	n++
+	w.c += len(p)
+	if _, err = w.Writer.Write(buf); err != nil {
+		return 0, err
+	}
 
-	return
+	return len(p), nil
+}
+
+// Close writes a trailing incomplete rune, if any.
+func (w *RawStringWriter) Close() error {
+	if len(w.tail) == 0 {
+		return nil
+	}
+
+	tail := w.tail
+	w.tail = nil
+	_, err := w.Writer.Write([]byte("` + \""))
+	if err == nil {
+		_, err = (&StringWriter{Writer: w.Writer}).Write(tail)
+	}
+	if err == nil {
+		_, err = w.Writer.Write([]byte("\" + `"))
+	}
+	return err
+}
+
+// rawSplices is the number of splices up to which text is written as a
+// raw string. Each splice adds to a chain of + operators, which is too
+// deep to parse for text with many of them, like CRLF line endings.
+const rawSplices = 64
+
+// isText reports whether r holds valid UTF-8 text, which can be written
+// as a raw string literal. It reads r until the end.
+func isText(r io.Reader) (bool, error) {
+	text, _, err := scanText(r)
+	return text, err
+}
+
+// scanText reports whether r holds valid UTF-8 text, along with the number
+// of characters a RawStringWriter splices in. It reads r until the end.
+func scanText(r io.Reader) (bool, int, error) {
+	splices := 0
+	br := bufio.NewReader(r)
+	for {
+		c, size, err := br.ReadRune()
+		if err == io.EOF {
+			return true, splices, nil
+		}
+		if err != nil {
+			return false, splices, err
+		}
+		if c == 0 || (c == utf8.RuneError && size == 1) {
+			return false, splices, nil
+		}
+		if c == '`' || c == '\r' || c == '\uFEFF' {
+			splices++
+		}
+	}
 }
@@ -0,0 +1,234 @@
+package bindata
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// evalLiteral evaluates a Go string constant expression.
+func evalLiteral(t *testing.T, expr string) []byte {
+	tv, err := types.Eval(token.NewFileSet(), nil, token.NoPos, expr)
+	if err != nil {
+		t.Fatalf("%s: %v", expr, err)
+	}
+	return []byte(constant.StringVal(tv.Value))
+}
+
+func TestStringWriters(t *testing.T) {
+	binary := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(binary)
+
+	tests := []struct {
+		data []byte
+		text bool
+	}{
+		{[]byte(""), true},
+		{[]byte("plain text\n"), true},
+		{[]byte("quotes \" and \\ and `backquotes` and \t tabs\r\n"), true},
+		{[]byte("\xef\xbb\xbfbom and été 日本 \U0001F600"), true},
+		{[]byte("\x0ab hex digits after escapes \x7f"), true},
+		{[]byte("nul \x00 byte"), false},
+		{[]byte("invalid \xff utf-8"), false},
+		{binary, false},
+	}
+
+	for i, test := range tests {
+		data := test.data
+		var buf bytes.Buffer
+		if _, err := io.Copy(&StringWriter{Writer: &buf}, iotest.OneByteReader(bytes.NewReader(data))); err != nil {
+			t.Fatal(err)
+		}
+		if out := evalLiteral(t, `"`+buf.String()+`"`); !bytes.Equal(out, data) {
+			t.Errorf("%d: string literal: expected %q, got %q", i, data, out)
+		}
+		if len(data) > 0 && buf.Len() > 3*len(data) {
+			t.Errorf("%d: string literal of %d bytes for %d bytes of data", i, buf.Len(), len(data))
+		}
+
+		text, err := isText(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != test.text {
+			t.Errorf("%d: expected text %v, got %v", i, test.text, text)
+		}
+		if !text {
+			continue
+		}
+
+		buf.Reset()
+		rw := &RawStringWriter{Writer: &buf}
+		if _, err := io.Copy(rw, iotest.OneByteReader(bytes.NewReader(data))); err != nil {
+			t.Fatal(err)
+		}
+		if err := rw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if out := evalLiteral(t, "`"+buf.String()+"`"); !bytes.Equal(out, data) {
+			t.Errorf("%d: raw string literal: expected %q, got %q", i, data, out)
+		}
+	}
+}
+
+func TestWrappedWriters(t *testing.T) {
+	data := []byte("first line\nsecond, longer line\n\nlast")
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&StringWriter{Writer: &buf, Width: 8}, iotest.OneByteReader(bytes.NewReader(data))); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	expected := []string{`first li" +`, `	"ne\n" +`, `	"second, " +`, `	"longer l" +`, `	"ine\n" +`, `	"\n" +`, `	"last`}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected lines %q, got %q", expected, lines)
+	}
+	if out := evalLiteral(t, `"`+buf.String()+`"`); !bytes.Equal(out, data) {
+		t.Errorf("expected %q, got %q", data, out)
+	}
+
+	buf.Reset()
+	if _, err := io.Copy(&ByteWriter{Writer: &buf, Width: 8}, iotest.OneByteReader(bytes.NewReader(data))); err != nil {
+		t.Fatal(err)
+	}
+	var rows []string
+	var out []byte
+	for _, row := range strings.Split(strings.TrimPrefix(buf.String(), "\n"), "\n") {
+		var values []string
+		for _, v := range strings.Fields(row) {
+			b, err := strconv.ParseUint(strings.TrimSuffix(v, ","), 0, 8)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, byte(b))
+			values = append(values, string(rune(b)))
+		}
+		rows = append(rows, strings.Join(values, ""))
+	}
+	expected = []string{"first li", "ne\n", "second, ", "longer l", "ine\n", "\n", "last"}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected rows %q, got %q", expected, rows)
+	}
+	if !bytes.Equal(out, data) {
+		t.Errorf("expected %q, got %q", data, out)
+	}
+}
+
+func TestWrappedStringWriterGroups(t *testing.T) {
+	data := make([]byte, 5*stringGroup+3)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	var buf bytes.Buffer
+	sw := &StringWriter{Writer: &buf, Width: 1}
+	if _, err := io.Copy(sw, iotest.OneByteReader(bytes.NewReader(data))); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString(`"`)
+	if err := sw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lines past the first group are concatenated in groups, so that the
+	// chain of + operators stays short.
+	if groups := strings.Count(buf.String(), "\n\t(\""); groups != 5 {
+		t.Errorf("expected 5 groups, got %d", groups)
+	}
+	if out := evalLiteral(t, `"`+buf.String()); !bytes.Equal(out, data) {
+		t.Errorf("expected %q, got %q", data, out)
+	}
+}
+
+func TestTranslateManySplices(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Text full of carriage returns and backquotes is written as an
+	// interpreted string, a raw string would splice in each of them.
+	in := filepath.Join(dir, "in")
+	writeTestFiles(t, map[string]string{
+		filepath.Join(in, "crlf.txt"):  strings.Repeat("line\r\n", 100000),
+		filepath.Join(in, "quote.js"):  strings.Repeat("var s = `x`;\n", 30000),
+		filepath.Join(in, "plain.txt"): "some `quoted` text\r\n",
+	})
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in}}
+	c.Prefix = in
+	c.Output = filepath.Join(dir, "bindata.go")
+	c.NoCompress = true
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(c.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = parser.ParseFile(token.NewFileSet(), c.Output, data, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("`some ` + \"`\" + `quoted` + \"`\" + ` text` + \"\\r\" + `\n`")) {
+		t.Errorf("expected text with few splices to be a raw string")
+	}
+	if !bytes.Contains(data, []byte(`"line\r\nline\r\n`)) {
+		t.Errorf("expected text with many splices to be an interpreted string")
+	}
+}
+
+func TestTranslateLargeBytes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := make([]byte, 4<<20)
+	rand.New(rand.NewSource(1)).Read(data)
+	in := filepath.Join(dir, "in")
+	writeTestFiles(t, map[string]string{filepath.Join(in, "large.bin"): string(data)})
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: in}}
+	c.Prefix = in
+	c.Output = filepath.Join(dir, "bindata.go")
+	c.NoCompress = true
+	c.Encoding = EncodingBytes
+
+	// The syntax tree holds a node for each byte, it is only built for a
+	// piece of the rows at a time and the literal is written as generated.
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if err := Translate(c); err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 256*uint64(len(data)) {
+		t.Errorf("expected at most 256 bytes to be allocated for each byte of data, got %d MB", alloc>>20)
+	}
+
+	out, err := ioutil.ReadFile(c.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := fmt.Sprintf("[]byte{\n\t%#02x, %#02x,", data[0], data[1])
+	if !bytes.Contains(out, []byte(row)) {
+		t.Errorf("expected output to contain %q", row)
+	}
+}
@@ -139,7 +139,7 @@ var _bintree = `)
 	return err
 }
 
-func writeTOCTree(w io.Writer, toc []Asset) error {
+func writeTOCTree(w io.Writer, c *Config, toc, dirs []Asset) error {
 	_, err := fmt.Fprintf(w, `// AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
@@ -153,7 +153,87 @@ func writeTOCTree(w io.Writer, toc []Asset) error {
 // AssetDir("data/img") would return []string{"a.png", "b.png"}
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error
 // AssetDir("") will return []string{"data"}.
-func AssetDir(name string) ([]string, error) {
+%s
+`, assetDirFunc(c))
+	if err != nil || c.dynamic() {
+		return err
+	}
+	tree := newAssetTree()
+	for i := range dirs {
+		pathList := strings.Split(dirs[i].Name, "/")
+		tree.Add(pathList, Asset{})
+	}
+	for i := range toc {
+		pathList := strings.Split(toc[i].Name, "/")
+		tree.Add(pathList, toc[i])
+	}
+	return tree.WriteAsGoMap(w)
+}
+
+// assetDirFunc returns the AssetDir function, which merges the directories
+// of the layers with the embedded ones if there are layers. Debug code
+// lists the directories found on disk.
+func assetDirFunc(c *Config) string {
+	if c.dynamic() {
+		layers := ""
+		if c.layered() {
+			layers = `
+	inLayers, isAsset := bindataLayersDir(cannonicalName, children)
+	if isAsset {
+		return nil, fmt.Errorf("Asset %s not found", name)
+	}
+	found = found || inLayers`
+		}
+		return `func AssetDir(name string) ([]string, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	children, found := bindataDiskDir(cannonicalName)` + layers + `
+	if !found {
+		return nil, fmt.Errorf("Asset %s not found", name)
+	}
+	rv := make([]string, 0, len(children))
+	for childName := range children {
+		rv = append(rv, childName)
+	}
+	return rv, nil
+}` + layersDirFunc(c)
+	}
+
+	if c.layered() {
+		return `func AssetDir(name string) ([]string, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	children := make(map[string]bool)
+	found := false
+	node := _bintree
+	if len(cannonicalName) != 0 {
+		for _, p := range strings.Split(cannonicalName, "/") {
+			node = node.Children[p]
+			if node == nil {
+				break
+			}
+		}
+	}
+	if node != nil {
+		if node.Func != nil {
+			return nil, fmt.Errorf("Asset %s not found", name)
+		}
+		found = true
+		for childName := range node.Children {
+			children[childName] = true
+		}
+	}
+	inLayers, isAsset := bindataLayersDir(cannonicalName, children)
+	if isAsset || !found && !inLayers {
+		return nil, fmt.Errorf("Asset %s not found", name)
+	}
+	rv := make([]string, 0, len(children))
+	for childName := range children {
+		rv = append(rv, childName)
+	}
+	return rv, nil
+}` + layersDirFunc(c)
+	}
+
+	return `func AssetDir(name string) ([]string, error) {
 	node := _bintree
 	if len(name) != 0 {
 		cannonicalName := strings.Replace(name, "\\", "/", -1)
@@ -161,35 +241,55 @@ func AssetDir(name string) ([]string, error) {
 		for _, p := range pathList {
 			node = node.Children[p]
 			if node == nil {
-				return nil, fmt.Errorf("Asset %%s not found", name)
+				return nil, fmt.Errorf("Asset %s not found", name)
 			}
 		}
 	}
 	if node.Func != nil {
-		return nil, fmt.Errorf("Asset %%s not found", name)
+		return nil, fmt.Errorf("Asset %s not found", name)
 	}
 	rv := make([]string, 0, len(node.Children))
 	for childName := range node.Children {
 		rv = append(rv, childName)
 	}
 	return rv, nil
+}`
 }
 
-`)
-	if err != nil {
-		return err
+// layersDirFunc returns bindataLayersDir, which AssetDir uses to merge the
+// children of a directory in the layers, if there are layers.
+func layersDirFunc(c *Config) string {
+	if !c.layered() {
+		return ""
 	}
-	tree := newAssetTree()
-	for i := range toc {
-		pathList := strings.Split(toc[i].Name, "/")
-		tree.Add(pathList, toc[i])
+	return `
+
+// bindataLayersDir adds the children of the named directory in the layers
+// to children. It reports whether a layer holds the directory, and whether
+// a layer holds an asset of that name instead.
+func bindataLayersDir(name string, children map[string]bool) (found, isAsset bool) {
+	prefix := ""
+	if len(name) != 0 {
+		prefix = name + "/"
 	}
-	return tree.WriteAsGoMap(w)
+	for _, layer := range bindataLayers() {
+		for _, n := range layer.names() {
+			if n == name {
+				return false, true
+			}
+			if strings.HasPrefix(n, prefix) {
+				found = true
+				children[strings.SplitN(n[len(prefix):], "/", 2)[0]] = true
+			}
+		}
+	}
+	return found, false
+}`
 }
 
 // writeTOC writes the table of contents file.
-func writeTOC(w io.Writer, toc []Asset) error {
-	err := writeTOCHeader(w)
+func writeTOC(w io.Writer, c *Config, toc []Asset) error {
+	err := writeTOCHeader(w, c)
 	if err != nil {
 		return err
 	}
@@ -212,14 +312,68 @@ func writeTOC(w io.Writer, toc []Asset) error {
 	return writeTOCFooter(w)
 }
 
-// writeTOCHeader writes the table of contents file header.
-func writeTOCHeader(w io.Writer) error {
+// writeDirs writes the table holding the metadata of the directories.
+func writeDirs(w io.Writer, c *Config, dirs []Asset) error {
+	_, err := fmt.Fprintf(w, `// _bindataDirs is a table, holding the info of each directory, mapped to its name.
+var _bindataDirs = map[string]os.FileInfo{
+`)
+	if err != nil {
+		return err
+	}
+
+	var maxlen = 0
+	for i := range dirs {
+		l := len(dirs[i].Name)
+		if l > maxlen {
+			maxlen = l
+		}
+	}
+
+	for i := range dirs {
+		size, mode, modTime, err := dirs[i].metadata(c)
+		if err != nil {
+			return err
+		}
+
+		filler := strings.Repeat(" ", maxlen-len(dirs[i].Name))
+		_, err = fmt.Fprintf(w, "\t%q: %sbindataFileInfo{name: %q, size: %d, mode: os.FileMode(%d), modTime: time.Unix(%d, 0)},\n",
+			dirs[i].Name, filler, dirs[i].Name, size, mode, modTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeTOCFooter(w)
+}
+
+// writeTOCHeader writes the table of contents file header. If there are
+// layers, assets are looked up in them before the embedded ones. Debug
+// code looks up the assets on disk.
+func writeTOCHeader(w io.Writer, c *Config) error {
+	lookup := "_bindata[cannonicalName]"
+	names := `names := make([]string, 0, len(_bindata))
+	for name := range _bindata {
+		names = append(names, name)
+	}
+	return names`
+	if c.layered() {
+		lookup = "bindataLookup(cannonicalName)"
+		names = "return bindataNames()"
+	} else if c.dynamic() {
+		lookup = "bindataDisk(cannonicalName)"
+		names = "return bindataDiskNames()"
+	}
+	dirInfo := "_bindataDirs[cannonicalName]"
+	if c.dynamic() {
+		dirInfo = "bindataDirInfo(cannonicalName)"
+	}
+
 	_, err := fmt.Fprintf(w, `// Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
 func Asset(name string) ([]byte, error) {
 	cannonicalName := strings.Replace(name, "\\", "/", -1)
-	if f, ok := _bindata[cannonicalName]; ok {
+	if f, ok := %[1]s; ok {
 		a, err := f()
 		if err != nil {
 			return nil, fmt.Errorf("Asset %%s can't read by error: %%v", name, err)
@@ -245,28 +399,27 @@ func MustAsset(name string) []byte {
 // could not be loaded.
 func AssetInfo(name string) (os.FileInfo, error) {
 	cannonicalName := strings.Replace(name, "\\", "/", -1)
-	if f, ok := _bindata[cannonicalName]; ok {
+	if f, ok := %[1]s; ok {
 		a, err := f()
 		if err != nil {
 			return nil, fmt.Errorf("AssetInfo %%s can't read by error: %%v", name, err)
 		}
 		return a.info, nil
 	}
+	if info, ok := %[3]s; ok {
+		return info, nil
+	}
 	return nil, fmt.Errorf("AssetInfo %%s not found", name)
 }
 
 // AssetNames returns the names of the assets.
 func AssetNames() []string {
-	names := make([]string, 0, len(_bindata))
-	for name := range _bindata {
-		names = append(names, name)
-	}
-	return names
+	%[2]s
 }
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-`)
+`, lookup, names, dirInfo)
 	return err
 }
 